@@ -12,6 +12,9 @@ type IssueReporter interface {
 // Issue represents a linter-issue
 type Issue struct {
 	Position token.Position
+	// End is the optional end position of the issue's range
+	// adding it changed the api, plugins built before have to be rebuilt (-u)
+	End      token.Position
 	Severity Severity
 	Category string
 	Message  string
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"go/token"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -16,34 +17,143 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-const configFileName = ".gomultilinter.yml"
+const (
+	configFileName = ".gomultilinter.yml"
+
+	// repoRootMarker marks the root directory of a repository
+	// which stops the search for config files
+	repoRootMarker = ".git"
+)
 
 // Config represents all possible configuration flags for gomultilinter
 // see newDefaultConfig for default values
 type Config struct {
+	// dir is the directory of the config file
+	dir string
+
+	// profileLinters are the ids of the linters of the selected profile
+	// nil if no profile with linters is selected, nested config files
+	// can only configure these linters
+	profileLinters map[string]bool
+
+	// mergedFiles are the absolute paths of the config files merged into the config
+	mergedFiles map[string]bool
+
+	// Root stops the search for config files in parent directories
+	Root bool `json:"root"`
+
+	// Extends are paths (relative to the config file) of config files
+	// which are merged before this config file
+	Extends []string `json:"extends,omitempty"`
+
 	// Verbose output
 	// only via cli flag
-	Verbose bool
+	Verbose bool `json:"-"`
+
+	// LinterSelection enables/disables linters by their name
+	// only via cli flags
+	LinterSelection *LinterSelection `json:"-"`
 
 	// ForceUpdate enforces rebuild
 	// of the linter plugins
 	ForceUpdate bool `json:"force_update"`
 
+	// StrictEnv if true, undefined environment variables without a default
+	// value result in an error instead of an empty string
+	StrictEnv bool `json:"strict_env"`
+
+	// SortIssues if true issues are sorted by their path, line, column and linter
+	// and issues reported by multiple linters are written only once
+	SortIssues bool `json:"sort_issues"`
+
+	// MaxIssues is the max number of written issues, 0 means unlimited
+	MaxIssues int `json:"max_issues"`
+
+	// MaxIssuesPerLinter is the max number of written issues per linter, 0 means unlimited
+	MaxIssuesPerLinter int `json:"max_issues_per_linter"`
+
+	// MaxIssuesPerFile is the max number of written issues per file, 0 means unlimited
+	MaxIssuesPerFile int `json:"max_issues_per_file"`
+
+	// MaxSameIssues is the max number of written issues with the same
+	// linter, category and message, 0 means unlimited
+	MaxSameIssues int `json:"max_same_issues"`
+
+	// OutputStyle is the style in which the issues are written
+	// auto (default), template, pretty, json, checkstyle or sarif
+	OutputStyle OutputStyle `json:"output_style"`
+
 	// OutputFormat go text/template which is used to print out issues
 	// see internal/checker/issue/LinterIssue for available fields
+	// and internal/checker/templateFuncs.go for available functions
 	OutputFormat string `json:"output_format"`
 
+	// Outputs are the destinations to which the issues are written
+	// if set, they replace the output to stdout/stderr by OutputStyle
+	// only read from the root config
+	Outputs []*OutputConfig `json:"outputs"`
+
 	// MinSeverity for which issues should be printed
 	MinSeverity *Severity `json:"min_severity"`
 
+	// FailOn is the min severity of issues which result in a non 0 exit status
+	FailOn *Severity `json:"fail_on"`
+
+	// SeverityRules remap the severity of issues reported by the linters
+	// the first matching rule applies
+	SeverityRules []*SeverityRule `json:"severity_rules"`
+
 	// Exclude can exclude issues based on their message, name or category
 	Exclude *ExcludeConfig `json:"exclude"`
 
+	// Generated configures the detection of generated files
+	// which are not linted
+	Generated *GeneratedConfig `json:"generated"`
+
+	// SkipTypeErrors if true linters are not run on packages which
+	// could not be parsed or type checked, the errors are reported anyway
+	SkipTypeErrors bool `json:"skip_type_errors"`
+
+	// SkipDirs are directories which are skipped when resolving targets recursively
+	// matched against whole segments of the paths (e.g. vendor, third_party/gen)
+	SkipDirs []string `json:"skip_dirs"`
+
+	// GitIgnore if true directories ignored by .gitignore files are skipped
+	// when resolving targets recursively
+	GitIgnore bool `json:"gitignore"`
+
+	// BuildContexts are the build contexts for which the packages are loaded and linted
+	// issues which occur in multiple contexts are reported once
+	// defaults to the context of the current platform
+	BuildContexts []*BuildContext `json:"build_contexts"`
+
+	// BuildTags are build tags which are added to all build contexts
+	BuildTags []string `json:"build_tags"`
+
 	// LinterInstallDirectory is the dir to which the linter plugins get installed
+	// environment variables and ~ are expanded in LinterInstallDirectory, Extends, SkipDirs,
+	// BuildTags, BuildContexts, the paths of Exclude, Generated and Outputs
+	// and the Name, Package and PluginPath of the linters
 	LinterInstallDirectory string `json:"linter_install_directory"`
 
 	// Linter which should be used
 	Linter []*LinterConfig `json:"linter"`
+
+	// Profiles which can be selected to override parts of the config
+	Profiles map[string]*Profile `json:"profiles,omitempty"`
+}
+
+// Profile overrides parts of the config if selected
+// fields which are not set do not override the config
+type Profile struct {
+	// MinSeverity overrides the min severity
+	MinSeverity *Severity `json:"min_severity,omitempty"`
+
+	// Exclude replaces the exclude config
+	Exclude *ExcludeConfig `json:"exclude,omitempty"`
+
+	// Linter replaces the linters
+	Linter []*LinterConfig `json:"linter,omitempty"`
 }
 
 // ExcludeConfig excludes Issues by matching
@@ -58,16 +168,113 @@ type ExcludeConfig struct {
 	// File-/Packagenames which should be excluded
 	Names MultiRegex `json:"names"`
 
+	// Paths are glob patterns of files which should be excluded
+	// relative to the directory of the config file
+	Paths MultiGlob `json:"paths"`
+
 	// Linter messages which should be excluded
 	Messages MultiRegex `json:"messages"`
 
 	// Linter cagtegories which should be excluded
 	Categories MultiRegex `json:"categories"`
+
+	// if true exclude rules which never matched do not result in an issue
+	UnusedRules bool `json:"unused_rules"`
+
+	// Rules exclude issues matching all of the rule's matchers
+	Rules []*ExcludeRule `json:"rules"`
+}
+
+// GeneratedConfig configures the detection of generated files
+// by default a file is generated if a line comment before the package clause
+// matches ^// Code generated .* DO NOT EDIT\.$
+type GeneratedConfig struct {
+	// Lint if true generated files are linted anyway
+	Lint bool `json:"lint"`
+
+	// Patterns are additional regular expressions matched against
+	// the comment lines before the package clause
+	Patterns MultiRegex `json:"patterns"`
+
+	// Paths are glob patterns of files which are treated as generated
+	// relative to the directory of the config file
+	Paths MultiGlob `json:"paths"`
+}
+
+// OutputConfig is a destination to which the issues are written
+type OutputConfig struct {
+	// Style is the style in which the issues are written, defaults to auto
+	Style OutputStyle `json:"style,omitempty"`
+
+	// Format is the go text/template of the template style
+	// defaults to the OutputFormat
+	Format string `json:"format,omitempty"`
+
+	// Path is the path of the file or stdout/stderr, defaults to stdout
+	// environment variables and ~ are expanded, relative paths are relative to the config file's dir
+	Path string `json:"path,omitempty"`
+
+	// MinSeverity of the written issues, defaults to all issues
+	MinSeverity *Severity `json:"min_severity,omitempty"`
+}
+
+// BuildContext is a build context for which the packages are loaded and linted
+type BuildContext struct {
+	// GOOS defaults to the GOOS of the current platform
+	GOOS string `json:"goos,omitempty"`
+
+	// GOARCH defaults to the GOARCH of the current platform
+	GOARCH string `json:"goarch,omitempty"`
+
+	// Tags are the build tags of the context
+	Tags []string `json:"tags,omitempty"`
+}
+
+// ExcludeRule excludes issues which match all of the provided matchers
+// matchers which are not set match any issue
+type ExcludeRule struct {
+	IssueMatcher
+
+	// Severity of the issue
+	Severity *Severity `json:"severity,omitempty"`
+
+	// position of the rule in the config file declaring it
+	position token.Position
+}
+
+// Position returns the position of the rule in the config file declaring it
+func (r *ExcludeRule) Position() token.Position {
+	return r.position
+}
+
+func (e *ExcludeConfig) setRulePositions(filename string, lines []int) {
+	for i, rule := range e.Rules {
+		rule.position = token.Position{Filename: filename}
+		if i < len(lines) {
+			rule.position.Line = lines[i]
+		}
+	}
+}
+
+// SeverityRule remaps the severity of issues which match
+// all of the provided matchers
+type SeverityRule struct {
+	IssueMatcher
+
+	// Severity the matching issues are remapped to
+	Severity *Severity `json:"severity"`
 }
 
 // LinterConfig represents a Linter which should be used
 // Package or PluginPath needs to be provided
 type LinterConfig struct {
+	// Name of the linter (as returned by Linter.Name())
+	// if set, linters which are not selected are not built/loaded at all
+	Name string `json:"name,omitempty"`
+
+	// Disabled linters are only used if enabled via cli flags
+	Disabled bool `json:"disabled,omitempty"`
+
 	// Package of the gomultilinter plugin
 	Package string `json:"package"`
 
@@ -76,17 +283,63 @@ type LinterConfig struct {
 
 	// Config is the Configuration for the concrete linter
 	Config json.RawMessage `json:"config"`
+
+	// ExpandEnv if true, environment variables and ~ are expanded
+	// in all string values of Config
+	ExpandEnv bool `json:"expand_env,omitempty"`
 }
 
 func newDefaultConfig() *Config {
 	return &Config{
 		MinSeverity:            &Severity{Severity: api.SeverityInfo},
+		FailOn:                 &Severity{Severity: api.SeverityInfo},
 		LinterInstallDirectory: os.ExpandEnv("$GOPATH/pkg/gomultilinter/linter"),
-		OutputFormat:           "{{.Path}}:{{.Line}}:{{if .Col}}{{.Col}}{{end}}:{{.Severity}}:{{.Category}}: {{.Message}} ({{.Linter}})",
+		OutputStyle:            OutputStyleAuto,
+		OutputFormat:           "{{.Path}}:{{.Line}}:{{if .Col}}{{.Col}}{{end}}:{{.Severity}}:{{.Category}}: {{.Message}} ({{.Linter}}){{if .BuildContexts}} {{.BuildContexts}}{{end}}",
 		Exclude:                new(ExcludeConfig),
+		Generated:              new(GeneratedConfig),
+		SkipDirs:               []string{".git", "vendor"},
+		GitIgnore:              true,
 	}
 }
 
+// SelectProfile applies the profile with the provided name to the config
+// the profile is applied once to the root config, nested config files
+// are merged on top of it (linters by their package/plugin path)
+// but their linters which are not part of the profile are dropped
+func (c *Config) SelectProfile(name string) error {
+	p, ok := c.Profiles[name]
+	if !ok {
+		log.WithFields("profile", name).Debug("profile not found")
+		return fmt.Errorf("profile %s not found", name)
+	}
+
+	if p.MinSeverity != nil {
+		minSeverity := *p.MinSeverity
+		c.MinSeverity = &minSeverity
+	}
+
+	if p.Exclude != nil {
+		exclude := *p.Exclude
+		c.Exclude = &exclude
+	}
+
+	if p.Linter != nil {
+		c.Linter = p.Linter
+		c.profileLinters = make(map[string]bool, len(p.Linter))
+		for _, l := range p.Linter {
+			c.profileLinters[l.id()] = true
+		}
+	}
+
+	return nil
+}
+
+// Marshal returns the yaml representation of the config
+func (c *Config) Marshal() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
 // SetVerbose initializes the logger with the corresponding severity
 func SetVerbose(verbose bool) {
 	if verbose {
@@ -94,34 +347,48 @@ func SetVerbose(verbose bool) {
 	}
 }
 
+// Dir returns the directory of the config file
+func (c *Config) Dir() string {
+	return c.dir
+}
+
 // ReadConfig reads the config file at the specified path
 // if the specified path is empty it searches from the current
-// directory upwards in the fs for a file named .gomultilinter.yml
+// directory upwards in the fs for files named .gomultilinter.yml
+// and merges them with the config files of their parent directories
+// until the root of the repository or a config file with root set is found
 // verbose, and forceUpdate are cli flags which can be provided and
 // override the flags from the configfile
 func ReadConfig(path string, verbose, forceUpdate bool) (*Config, error) {
+	var paths []string
 	if path == "" {
 		wd, err := os.Getwd()
 		if err != nil {
 			log.WithFields("err", err).Debug("config: failed to read working directory")
 			return nil, errors.New("reading config failed")
 		}
-		path = findConfigFile(wd)
+
+		paths, err = findConfigFiles(wd)
+		if err != nil {
+			return nil, err
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("no %s found", configFileName)
+		}
 	} else if !files.FileExists(path) {
 		return nil, fmt.Errorf("config file at %v not found", path)
+	} else {
+		paths = []string{path}
 	}
 
-	log.WithFields("path", path).Debug("using config file")
-
 	conf := newDefaultConfig()
+	for _, p := range paths {
+		log.WithFields("path", p).Debug("using config file")
 
-	confFileContent, err := ioutil.ReadFile(path)
-	if err != nil {
-		log.WithFields("err", err).Fatal("could not read config file")
-	}
-
-	if err := yaml.Unmarshal(confFileContent, conf); err != nil {
-		log.WithFields("err", err).Fatal("could not read config file")
+		var err error
+		if conf, err = conf.mergeFile(p); err != nil {
+			return nil, err
+		}
 	}
 
 	// overwrite cli flags
@@ -133,14 +400,81 @@ func ReadConfig(path string, verbose, forceUpdate bool) (*Config, error) {
 	return conf, nil
 }
 
-func findConfigFile(path string) string {
-	file := filepath.Join(path, configFileName)
-	if !files.FileExists(file) {
+// ValidateFile validates the config file at path
+// including the config files it extends
+func ValidateFile(path string) error {
+	if !files.FileExists(path) {
+		return fmt.Errorf("config file at %v not found", path)
+	}
+
+	_, err := newDefaultConfig().mergeFile(path)
+	return err
+}
+
+// findConfigFiles returns the config files from the specified directory upwards
+// until the root of the repository (containing .git) or a config file with root set
+// ordered from the outermost to the innermost
+// if the repository contains no config file, the nearest one above it is used (e.g. in $HOME)
+func findConfigFiles(path string) ([]string, error) {
+	var paths []string
+	outsideRepo := false
+	for {
+		file := filepath.Join(path, configFileName)
+		if files.FileExists(file) {
+			paths = append([]string{file}, paths...)
+			if outsideRepo {
+				return paths, nil
+			}
+
+			root, err := isRootConfigFile(file)
+			if err != nil {
+				return nil, err
+			}
+			if root {
+				return paths, nil
+			}
+		}
+
+		if marker := filepath.Join(path, repoRootMarker); files.FileExists(marker) || files.DirExists(marker) {
+			if len(paths) > 0 {
+				return paths, nil
+			}
+			outsideRepo = true
+		}
+
 		parent := filepath.Dir(path)
 		if parent == path {
-			return ""
+			return paths, nil
 		}
-		return findConfigFile(parent)
+		path = parent
+	}
+}
+
+func isRootConfigFile(path string) (bool, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.WithFields("err", err, "path", path).Debug("could not read config file")
+		return false, fmt.Errorf("could not read config file %s: %v", path, err)
+	}
+
+	var conf struct {
+		Root bool `json:"root"`
+	}
+	if err := yaml.Unmarshal(content, &conf); err != nil {
+		log.WithFields("err", err, "path", path).Debug("could not parse config file")
+		return false, fmt.Errorf("could not parse config file %s: %v", path, err)
+	}
+
+	return conf.Root, nil
+}
+
+// setDir makes the path of the output file relative to the dir
+func (o *OutputConfig) setDir(dir string) {
+	switch o.Path {
+	case "", OutputPathStdout, OutputPathStderr:
+		return
+	}
+	if !filepath.IsAbs(o.Path) {
+		o.Path = filepath.Join(dir, o.Path)
 	}
-	return file
 }
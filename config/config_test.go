@@ -0,0 +1,164 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liut0/gomultilinter/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadConfigExtends(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "gomultilinter")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "base.yml"), []byte(`
+min_severity: warning
+exclude:
+  names:
+    - 'base'
+outputs:
+  - path: 'reports/issues.json'
+`), os.ModePerm))
+
+	writeTestConfig(t, filepath.Join(dir, "sub"), `
+extends:
+  - '../base.yml'
+exclude:
+  names:
+    - 'sub'
+`)
+
+	conf, err := ReadConfig(filepath.Join(dir, "sub", configFileName), false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, api.SeverityWarning, conf.MinSeverity.Severity)
+	assert.Equal(t, "base", conf.Exclude.Names[0].String())
+	assert.Equal(t, "sub", conf.Exclude.Names[1].String())
+	assert.Equal(t, filepath.Join(dir, "sub"), conf.Dir())
+	assert.Empty(t, conf.Extends)
+	// outputs are inherited with their paths relative to the file declaring them
+	assert.Equal(t, filepath.Join(dir, "reports", "issues.json"), conf.Outputs[0].Path)
+
+	writeTestConfig(t, filepath.Join(dir, "cycle"), `
+extends:
+  - '.gomultilinter.yml'
+`)
+	_, err = ReadConfig(filepath.Join(dir, "cycle", configFileName), false, false)
+	assert.Error(t, err)
+}
+
+func TestSelectProfile(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "gomultilinter")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeTestConfig(t, dir, `
+linter:
+  - &golint
+    package: 'golint'
+  - package: 'errcheck'
+profiles:
+  fast:
+    min_severity: error
+    linter:
+      - *golint
+`)
+	writeTestConfig(t, filepath.Join(dir, "sub"), `
+exclude:
+  names:
+    - 'sub'
+linter:
+  - package: 'maligned'
+  - package: 'golint'
+    config:
+      min_confidence: 0.5
+`)
+
+	conf, err := ReadConfig(filepath.Join(dir, configFileName), false, false)
+	assert.NoError(t, err)
+	assert.Len(t, conf.Linter, 2)
+
+	assert.Error(t, conf.SelectProfile("slow"))
+	assert.NoError(t, conf.SelectProfile("fast"))
+	assert.Equal(t, api.SeverityError, conf.MinSeverity.Severity)
+	assert.Len(t, conf.Linter, 1)
+	assert.Equal(t, "golint", conf.Linter[0].Package)
+
+	nested, err := NewTree(conf).ForDir(filepath.Join(dir, "sub"))
+	assert.NoError(t, err)
+	assert.Equal(t, api.SeverityError, nested.MinSeverity.Severity)
+	assert.Len(t, nested.Exclude.Names, 1)
+
+	// the profile holds in nested directories, only its linters can be configured
+	assert.Len(t, nested.Linter, 1)
+	assert.Equal(t, "golint", nested.Linter[0].Package)
+	assert.JSONEq(t, `{"min_confidence": 0.5}`, string(nested.Linter[0].Config))
+}
+
+func TestExcludePaths(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "gomultilinter")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeTestConfig(t, dir, `
+exclude:
+  paths:
+    - 'internal/**/zz_generated_*.go'
+`)
+	writeTestConfig(t, filepath.Join(dir, "sub"), `
+exclude:
+  paths:
+    - '*_mock.go'
+`)
+
+	conf, err := ReadConfig(filepath.Join(dir, configFileName), false, false)
+	assert.NoError(t, err)
+	assert.True(t, conf.Exclude.Paths.MatchesAny(filepath.Join(dir, "internal", "a", "zz_generated_foo.go")))
+	assert.False(t, conf.Exclude.Paths.MatchesAny(filepath.Join(dir, "sub", "internal", "zz_generated_foo.go")))
+	assert.False(t, conf.Exclude.Paths.MatchesAny(filepath.Join(dir, "foo_mock.go")))
+
+	nested, err := NewTree(conf).ForDir(filepath.Join(dir, "sub"))
+	assert.NoError(t, err)
+	assert.True(t, nested.Exclude.Paths.MatchesAny(filepath.Join(dir, "internal", "zz_generated_foo.go")))
+	assert.True(t, nested.Exclude.Paths.MatchesAny(filepath.Join(dir, "sub", "foo_mock.go")))
+	assert.False(t, nested.Exclude.Paths.MatchesAny(filepath.Join(dir, "foo_mock.go")))
+}
+
+func TestExcludeRulePositions(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "gomultilinter")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeTestConfig(t, dir, `
+exclude:
+  rules:
+    - linter: 'errcheck'
+    - path: '_test\.go$'
+      message: 'foo'
+profiles:
+  fast:
+    exclude:
+      rules:
+        - category: 'comments'
+`)
+
+	conf, err := ReadConfig(filepath.Join(dir, configFileName), false, false)
+	assert.NoError(t, err)
+
+	path := filepath.Join(dir, configFileName)
+	assert.Equal(t, path, conf.Exclude.Rules[0].Position().Filename)
+	assert.Equal(t, 4, conf.Exclude.Rules[0].Position().Line)
+	assert.Equal(t, 5, conf.Exclude.Rules[1].Position().Line)
+	assert.Equal(t, 11, conf.Profiles["fast"].Exclude.Rules[0].Position().Line)
+}
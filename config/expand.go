@@ -0,0 +1,227 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"github.com/liut0/gomultilinter/internal/glob"
+)
+
+const (
+	homeDirPrefix     = "~"
+	envDefaultDivider = ":-"
+)
+
+// expander expands environment variables (${VAR}, ${VAR:-default}, $VAR)
+// and a leading ~ to the home directory of the current user
+// other $ characters (e.g. $1 or an anchor of a regex) are kept as is
+type expander struct {
+	// strict results in an error for undefined variables without default
+	strict bool
+}
+
+func (e *expander) expand(s string) (string, error) {
+	var undefined []string
+
+	expanded := expandEnv(s, func(name string) string {
+		name, defaultVal, hasDefault := splitEnvDefault(name)
+		if val, ok := os.LookupEnv(name); ok && (val != "" || !hasDefault) {
+			return val
+		}
+		if hasDefault {
+			return defaultVal
+		}
+
+		undefined = append(undefined, name)
+		return ""
+	})
+
+	if e.strict && len(undefined) > 0 {
+		return "", fmt.Errorf("undefined environment variables %s in %q", strings.Join(undefined, ", "), s)
+	}
+
+	return expandHomeDir(expanded)
+}
+
+// expandAll expands all of the provided strings in place
+func (e *expander) expandAll(strs ...*string) error {
+	for _, s := range strs {
+		expanded, err := e.expand(*s)
+		if err != nil {
+			return err
+		}
+		*s = expanded
+	}
+	return nil
+}
+
+// expandGlobs expands the patterns of the globs in place
+func (e *expander) expandGlobs(globs MultiGlob) error {
+	for _, g := range globs {
+		if err := e.expandAll(&g.pattern); err != nil {
+			return err
+		}
+		if err := glob.Validate(g.pattern); err != nil {
+			return fmt.Errorf("invalid glob pattern %q: %v", g.pattern, err)
+		}
+	}
+	return nil
+}
+
+// expandRegex expands the source of the regex and recompiles it if it changed
+func (e *expander) expandRegex(r *Regex) error {
+	if r == nil || r.Regexp == nil {
+		return nil
+	}
+
+	expanded, err := e.expand(r.String())
+	if err != nil || expanded == r.String() {
+		return err
+	}
+
+	return r.UnmarshalText([]byte(expanded))
+}
+
+// expandExclude expands the names, paths and the path of the rules of the exclude config
+func (e *expander) expandExclude(exclude *ExcludeConfig) error {
+	if exclude == nil {
+		return nil
+	}
+
+	for _, name := range exclude.Names {
+		if err := e.expandRegex(name); err != nil {
+			return err
+		}
+	}
+
+	if err := e.expandGlobs(exclude.Paths); err != nil {
+		return err
+	}
+
+	for _, rule := range exclude.Rules {
+		if err := e.expandRegex(rule.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *expander) expandLinter(linter []*LinterConfig) error {
+	for _, l := range linter {
+		if err := e.expandAll(&l.Name, &l.Package, &l.PluginPath); err != nil {
+			return err
+		}
+
+		if !l.ExpandEnv || l.Config == nil {
+			continue
+		}
+
+		var val interface{}
+		if err := json.Unmarshal(l.Config, &val); err != nil {
+			return err
+		}
+
+		val, err := e.expandValue(val)
+		if err != nil {
+			return err
+		}
+
+		if l.Config, err = json.Marshal(val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandValue expands all strings of a decoded json value
+func (e *expander) expandValue(val interface{}) (interface{}, error) {
+	var err error
+	switch v := val.(type) {
+	case string:
+		return e.expand(v)
+	case []interface{}:
+		for i := range v {
+			if v[i], err = e.expandValue(v[i]); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]interface{}:
+		for k := range v {
+			if v[k], err = e.expandValue(v[k]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return val, nil
+}
+
+// expandEnv replaces ${name} and $name in s by the result of mapping
+// unlike os.Expand, $ which is not followed by { or a name
+// and unterminated ${ are kept as is
+func expandEnv(s string, mapping func(name string) string) string {
+	var expanded strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			expanded.WriteByte(s[i])
+			continue
+		}
+
+		if s[i+1] == '{' {
+			end := strings.IndexByte(s[i+2:], '}')
+			if end < 0 {
+				expanded.WriteByte(s[i])
+				continue
+			}
+			expanded.WriteString(mapping(s[i+2 : i+2+end]))
+			i += end + 2
+			continue
+		}
+
+		end := i + 1
+		for end < len(s) && isEnvNameChar(s[end], end == i+1) {
+			end++
+		}
+		if end == i+1 {
+			expanded.WriteByte(s[i])
+			continue
+		}
+		expanded.WriteString(mapping(s[i+1 : end]))
+		i = end - 1
+	}
+	return expanded.String()
+}
+
+// isEnvNameChar returns wether c is part of an environment variable name
+// names start with a letter or _
+func isEnvNameChar(c byte, first bool) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && c >= '0' && c <= '9')
+}
+
+func splitEnvDefault(name string) (string, string, bool) {
+	i := strings.Index(name, envDefaultDivider)
+	if i < 0 {
+		return name, "", false
+	}
+	return name[:i], name[i+len(envDefaultDivider):], true
+}
+
+func expandHomeDir(path string) (string, error) {
+	if path != homeDirPrefix && !strings.HasPrefix(path, homeDirPrefix+string(filepath.Separator)) {
+		return path, nil
+	}
+
+	home := os.Getenv("HOME")
+	if home == "" {
+		u, err := user.Current()
+		if err != nil {
+			return "", fmt.Errorf("could not expand home directory of %q: %v", path, err)
+		}
+		home = u.HomeDir
+	}
+
+	return home + strings.TrimPrefix(path, homeDirPrefix), nil
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpander(t *testing.T) {
+	assert.NoError(t, os.Setenv("GOMULTILINTER_TEST_VAR", "foo"))
+	home := os.Getenv("HOME")
+	defer os.Setenv("HOME", home)
+	assert.NoError(t, os.Setenv("HOME", "/home/test"))
+
+	e := &expander{}
+	for in, expected := range map[string]string{
+		"$GOMULTILINTER_TEST_VAR/x":                 "foo/x",
+		"${GOMULTILINTER_TEST_VAR}/x":               "foo/x",
+		"${GOMULTILINTER_TEST_VAR:-bar}/x":          "foo/x",
+		"${GOMULTILINTER_TEST_UNDEFINED:-bar}/x":    "bar/x",
+		"${GOMULTILINTER_TEST_UNDEFINED}/x":         "/x",
+		"~/plugin.so":                               "/home/test/plugin.so",
+		"~":                                         "/home/test",
+		"/x/~/y":                                    "/x/~/y",
+		"~${GOMULTILINTER_TEST_UNDEFINED:-}/x":      "/home/test/x",
+		"${GOMULTILINTER_TEST_UNDEFINED:-~}/plugin": "/home/test/plugin",
+		`^(a|b)$1\.go$`:                             `^(a|b)$1\.go$`,
+		"$? $$ $ ${":                                "$? $$ $ ${",
+		"a$GOMULTILINTER_TEST_VAR.b":                "afoo.b",
+	} {
+		actual, err := e.expand(in)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, in)
+	}
+
+	e.strict = true
+	_, err := e.expand("${GOMULTILINTER_TEST_UNDEFINED}/x")
+	assert.Error(t, err)
+
+	actual, err := e.expand("${GOMULTILINTER_TEST_UNDEFINED:-bar}/x")
+	assert.NoError(t, err)
+	assert.Equal(t, "bar/x", actual)
+
+	l := &LinterConfig{
+		PluginPath: "~/plugin.so",
+		Config:     []byte(`{"dirs":["$GOMULTILINTER_TEST_VAR"],"n":1}`),
+		ExpandEnv:  true,
+	}
+	assert.NoError(t, e.expandLinter([]*LinterConfig{l}))
+	assert.Equal(t, "/home/test/plugin.so", l.PluginPath)
+	assert.JSONEq(t, `{"dirs":["foo"],"n":1}`, string(l.Config))
+}
+
+func TestReadConfigExpand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gomultilinter")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	home := os.Getenv("HOME")
+	defer os.Setenv("HOME", home)
+	assert.NoError(t, os.Setenv("HOME", "/home/test"))
+	assert.NoError(t, os.Setenv("GOMULTILINTER_TEST_VAR", "foo"))
+
+	writeTestConfig(t, dir, `
+skip_dirs:
+  - '${GOMULTILINTER_TEST_UNDEFINED:-gen}'
+  - '~/cache'
+build_tags:
+  - '${GOMULTILINTER_TEST_VAR}'
+build_contexts:
+  - goos: '${GOMULTILINTER_TEST_UNDEFINED:-windows}'
+    tags: ['$GOMULTILINTER_TEST_VAR']
+exclude:
+  names:
+    - '^${GOMULTILINTER_TEST_VAR}/(a|b)$'
+  paths:
+    - '~/src/*.go'
+    - '${GOMULTILINTER_TEST_UNDEFINED:-internal}/*.go'
+  rules:
+    - path: '/${GOMULTILINTER_TEST_VAR}/.*\.go$'
+generated:
+  paths:
+    - '${GOMULTILINTER_TEST_VAR}/*.go'
+outputs:
+  - path: '${GOMULTILINTER_TEST_UNDEFINED:-reports}/issues.json'
+  - path: '~/issues.sarif'
+  - path: 'stderr'
+`)
+
+	conf, err := ReadConfig(filepath.Join(dir, configFileName), false, false)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"gen", "/home/test/cache"}, conf.SkipDirs)
+	assert.Equal(t, []string{"foo"}, conf.BuildTags)
+	assert.Equal(t, "windows", conf.BuildContexts[0].GOOS)
+	assert.Equal(t, []string{"foo"}, conf.BuildContexts[0].Tags)
+
+	assert.True(t, conf.Exclude.Paths.MatchesAny("/home/test/src/a.go"))
+	assert.False(t, conf.Exclude.Paths.MatchesAny(filepath.Join(dir, "src", "a.go")))
+	assert.True(t, conf.Exclude.Paths.MatchesAny(filepath.Join(dir, "internal", "a.go")))
+
+	assert.Equal(t, `^foo/(a|b)$`, conf.Exclude.Names[0].String())
+	assert.True(t, conf.Exclude.Names[0].MatchString("foo/b"))
+
+	assert.Equal(t, `/foo/.*\.go$`, conf.Exclude.Rules[0].Path.String())
+	assert.True(t, conf.Exclude.Rules[0].Path.MatchString("/x/foo/a.go"))
+
+	assert.True(t, conf.Generated.Paths.MatchesAny(filepath.Join(dir, "foo", "a.go")))
+
+	assert.Equal(t, filepath.Join(dir, "reports", "issues.json"), conf.Outputs[0].Path)
+	assert.Equal(t, "/home/test/issues.sarif", conf.Outputs[1].Path)
+	assert.Equal(t, OutputPathStderr, conf.Outputs[2].Path)
+}
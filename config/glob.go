@@ -0,0 +1,70 @@
+package config
+
+import (
+	"path/filepath"
+
+	"github.com/liut0/gomultilinter/internal/files"
+	"github.com/liut0/gomultilinter/internal/glob"
+)
+
+// Glob is an JSON-Compatible glob pattern (supporting **) which is matched
+// against paths relative to the directory of the config file declaring it
+// absolute patterns (e.g. expanded from ~) are matched against the absolute paths
+type Glob struct {
+	pattern string
+	dir     string
+}
+
+// MultiGlob wraps multiple glob patterns
+type MultiGlob []*Glob
+
+// MatchesAny returns true if any of the globs matches the path
+func (g MultiGlob) MatchesAny(path string) bool {
+	for _, gl := range g {
+		if gl.Matches(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches returns true if the path relative to the glob's directory matches
+// paths outside of the directory do not match
+func (g *Glob) Matches(path string) bool {
+	path = files.AbsPath(path)
+	if filepath.IsAbs(g.pattern) {
+		return glob.Match(filepath.ToSlash(g.pattern), filepath.ToSlash(path))
+	}
+
+	if !files.ContainsPath(g.dir, path) {
+		return false
+	}
+
+	rel, err := filepath.Rel(g.dir, path)
+	if err != nil {
+		return false
+	}
+
+	return glob.Match(g.pattern, filepath.ToSlash(rel))
+}
+
+func (g *Glob) String() string {
+	return g.pattern
+}
+
+// UnmarshalText validates the provided glob pattern
+func (g *Glob) UnmarshalText(data []byte) error {
+	g.pattern = string(data)
+	return glob.Validate(g.pattern)
+}
+
+// MarshalText returns the glob pattern
+func (g *Glob) MarshalText() ([]byte, error) {
+	return []byte(g.pattern), nil
+}
+
+func (g MultiGlob) setDir(dir string) {
+	for _, gl := range g {
+		gl.dir = dir
+	}
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IssueMatcher matches issues by regular expressions
+// matchers which are not set match any input
+type IssueMatcher struct {
+	// Path regular expression matched against the abs path of the issue's file
+	Path *Regex `json:"path,omitempty"`
+
+	// Linter regular expression matched against the name of the linter
+	Linter *Regex `json:"linter,omitempty"`
+
+	// Category regular expression matched against the issue's category
+	Category *Regex `json:"category,omitempty"`
+
+	// Message regular expression matched against the issue's message
+	Message *Regex `json:"message,omitempty"`
+}
+
+// Matches returns true if all of the set matchers match
+func (m *IssueMatcher) Matches(path, linter, category, message string) bool {
+	return m.Path.matchesOrUnset(path) &&
+		m.Linter.matchesOrUnset(linter) &&
+		m.Category.matchesOrUnset(category) &&
+		m.Message.matchesOrUnset(message)
+}
+
+func (m *IssueMatcher) String() string {
+	var matchers []string
+	for _, matcher := range []struct {
+		name string
+		rgx  *Regex
+	}{
+		{"path", m.Path},
+		{"linter", m.Linter},
+		{"category", m.Category},
+		{"message", m.Message},
+	} {
+		if matcher.rgx != nil {
+			matchers = append(matchers, fmt.Sprintf("%s=%q", matcher.name, matcher.rgx.String()))
+		}
+	}
+
+	return strings.Join(matchers, " ")
+}
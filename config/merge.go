@@ -0,0 +1,399 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/liut0/gomultilinter/internal/files"
+	"github.com/liut0/gomultilinter/internal/log"
+)
+
+// mergeFile reads the config file at path and merges it into a copy of c
+// config files the file extends are merged before the file itself
+func (c *Config) mergeFile(path string) (*Config, error) {
+	return c.mergeFileExtending(files.AbsPath(path), map[string]bool{}, false)
+}
+
+// mergeNestedFile merges the nested config file at path like mergeFile
+// but rejects the keys which are only read from the root config
+// in the file and the config files it extends
+func (c *Config) mergeNestedFile(path string) (*Config, error) {
+	return c.mergeFileExtending(files.AbsPath(path), map[string]bool{}, true)
+}
+
+// mergeFileExtending merges the config file at path and the files it extends
+// files which are already merged into c (e.g. a preset extended by the root and a nested config) are skipped
+func (c *Config) mergeFileExtending(path string, visited map[string]bool, nested bool) (*Config, error) {
+	if visited[path] {
+		log.WithFields("path", path).Debug("config extends itself")
+		return nil, fmt.Errorf("config file %s extends itself", path)
+	}
+	if c.mergedFiles[path] {
+		log.WithFields("path", path).Debug("config file is already merged")
+		return c, nil
+	}
+	visited[path] = true
+	defer delete(visited, path)
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.WithFields("err", err, "path", path).Debug("could not read config file")
+		return nil, fmt.Errorf("could not read config file %s: %v", path, err)
+	}
+
+	if err := validateConfigFile(path, content); err != nil {
+		log.WithFields("err", err, "path", path).Debug("invalid config file")
+		return nil, err
+	}
+
+	if nested {
+		if err := validateNestedConfigFile(path, content); err != nil {
+			log.WithFields("err", err, "path", path).Debug("invalid nested config file")
+			return nil, err
+		}
+	}
+
+	var extending struct {
+		Extends   []string `json:"extends"`
+		StrictEnv *bool    `json:"strict_env"`
+	}
+	if err := yaml.Unmarshal(content, &extending); err != nil {
+		log.WithFields("err", err, "path", path).Debug("could not parse config file")
+		return nil, fmt.Errorf("could not parse config file %s: %v", path, err)
+	}
+
+	e := &expander{strict: c.StrictEnv}
+	if extending.StrictEnv != nil {
+		e.strict = *extending.StrictEnv
+	}
+
+	dir := filepath.Dir(path)
+	base := c
+	for _, basePath := range extending.Extends {
+		if basePath, err = e.expand(basePath); err != nil {
+			return nil, fmt.Errorf("could not parse config file %s: %v", path, err)
+		}
+		if !filepath.IsAbs(basePath) {
+			basePath = filepath.Join(dir, basePath)
+		}
+
+		log.WithFields("path", path, "base", basePath).Debug("extending config file")
+		if base, err = base.mergeFileExtending(basePath, visited, nested); err != nil {
+			return nil, err
+		}
+	}
+
+	merged, err := base.merge(content, path)
+	if err != nil {
+		log.WithFields("err", err, "path", path).Debug("could not parse config file")
+		return nil, fmt.Errorf("could not parse config file %s: %v", path, err)
+	}
+
+	merged.dir = dir
+	merged.Extends = nil
+	merged.mergedFiles = make(map[string]bool, len(base.mergedFiles)+1)
+	for p := range base.mergedFiles {
+		merged.mergedFiles[p] = true
+	}
+	merged.mergedFiles[path] = true
+	return merged, nil
+}
+
+// merge applies the yaml content of the config file at path to a copy of c
+//
+// values which are set in content override the ones of c,
+// profiles of content replace the ones of c with the same name,
+// build contexts, build tags, skip dirs and outputs of content replace the ones of c
+// (relative output paths are resolved against the file's dir),
+// exclude and generated lists are appended to the ones of c (paths stay relative to the file's dir),
+// severity rules of content take precedence over the ones of c and
+// linters are merged by their package/plugin path where the
+// linter's config is deep merged and disabled is taken from content
+// (only the linters of the selected profile if c has one)
+func (c *Config) merge(content []byte, path string) (*Config, error) {
+	dir := filepath.Dir(path)
+	merged := c.clone()
+	merged.Linter = nil
+	merged.Profiles = nil
+	merged.SeverityRules = nil
+	merged.Exclude.Names = nil
+	merged.Exclude.Paths = nil
+	merged.Exclude.Messages = nil
+	merged.Exclude.Categories = nil
+	merged.Exclude.Rules = nil
+	merged.Generated.Patterns = nil
+	merged.Generated.Paths = nil
+	merged.BuildContexts = nil
+	merged.BuildTags = nil
+	merged.SkipDirs = nil
+	merged.Outputs = nil
+
+	if err := yaml.Unmarshal(content, merged); err != nil {
+		return nil, err
+	}
+
+	if merged.Exclude == nil {
+		merged.Exclude = new(ExcludeConfig)
+	}
+	if merged.Generated == nil {
+		merged.Generated = new(GeneratedConfig)
+	}
+
+	// lists are not yet merged with the ones of c, so only the values of content are expanded
+	if err := merged.expand(c); err != nil {
+		return nil, err
+	}
+
+	if merged.BuildContexts == nil {
+		merged.BuildContexts = c.BuildContexts
+	}
+	if merged.BuildTags == nil {
+		merged.BuildTags = c.BuildTags
+	}
+	if merged.SkipDirs == nil {
+		merged.SkipDirs = c.SkipDirs
+	}
+	if merged.Outputs == nil {
+		merged.Outputs = c.Outputs
+	} else {
+		for _, o := range merged.Outputs {
+			o.setDir(dir)
+		}
+	}
+
+	merged.Exclude.Paths.setDir(dir)
+	merged.Exclude.setRulePositions(path, sequenceLines(content, "exclude", "rules"))
+	merged.Generated.Paths.setDir(dir)
+	for name, p := range merged.Profiles {
+		if p.Exclude != nil {
+			p.Exclude.Paths.setDir(dir)
+			p.Exclude.setRulePositions(path, sequenceLines(content, "profiles", name, "exclude", "rules"))
+		}
+	}
+
+	merged.Profiles = mergeProfiles(c.Profiles, merged.Profiles)
+
+	merged.Linter = mergeLinter(c.Linter, merged.Linter)
+	if c.profileLinters != nil {
+		merged.Linter = profileLinter(merged.Linter, c.profileLinters)
+	}
+	merged.SeverityRules = append(merged.SeverityRules, c.SeverityRules...)
+	merged.Exclude.Names = append(append(MultiRegex{}, c.Exclude.Names...), merged.Exclude.Names...)
+	merged.Exclude.Paths = append(append(MultiGlob{}, c.Exclude.Paths...), merged.Exclude.Paths...)
+	merged.Exclude.Messages = append(append(MultiRegex{}, c.Exclude.Messages...), merged.Exclude.Messages...)
+	merged.Exclude.Categories = append(append(MultiRegex{}, c.Exclude.Categories...), merged.Exclude.Categories...)
+	merged.Exclude.Rules = append(append([]*ExcludeRule{}, c.Exclude.Rules...), merged.Exclude.Rules...)
+	merged.Generated.Patterns = append(append(MultiRegex{}, c.Generated.Patterns...), merged.Generated.Patterns...)
+	merged.Generated.Paths = append(append(MultiGlob{}, c.Generated.Paths...), merged.Generated.Paths...)
+
+	return merged, nil
+}
+
+// clone returns a copy of c which can be modified
+// without affecting c, list elements are shared
+func (c *Config) clone() *Config {
+	cloned := *c
+
+	exclude := *c.Exclude
+	cloned.Exclude = &exclude
+
+	generated := *c.Generated
+	cloned.Generated = &generated
+
+	if c.MinSeverity != nil {
+		minSeverity := *c.MinSeverity
+		cloned.MinSeverity = &minSeverity
+	}
+
+	if c.FailOn != nil {
+		failOn := *c.FailOn
+		cloned.FailOn = &failOn
+	}
+
+	if c.Profiles != nil {
+		cloned.Profiles = make(map[string]*Profile, len(c.Profiles))
+		for name, p := range c.Profiles {
+			cloned.Profiles[name] = p
+		}
+	}
+
+	return &cloned
+}
+
+func (l *LinterConfig) id() string {
+	if l.PluginPath != "" {
+		return l.PluginPath
+	}
+	return l.Package
+}
+
+func mergeProfiles(parent, child map[string]*Profile) map[string]*Profile {
+	if len(child) == 0 {
+		return parent
+	}
+
+	merged := make(map[string]*Profile, len(parent)+len(child))
+	for name, p := range parent {
+		merged[name] = p
+	}
+	for name, p := range child {
+		merged[name] = p
+	}
+	return merged
+}
+
+func mergeLinter(parent, child []*LinterConfig) []*LinterConfig {
+	merged := append([]*LinterConfig{}, parent...)
+
+	for _, childLinter := range child {
+		found := false
+		for i, linter := range merged {
+			if linter.id() == childLinter.id() {
+				name := childLinter.Name
+				if name == "" {
+					name = linter.Name
+				}
+
+				merged[i] = &LinterConfig{
+					Name:       name,
+					Disabled:   childLinter.Disabled,
+					Package:    childLinter.Package,
+					PluginPath: childLinter.PluginPath,
+					Config:     mergeRawConfig(linter.Config, childLinter.Config),
+				}
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			merged = append(merged, childLinter)
+		}
+	}
+
+	return merged
+}
+
+// profileLinter returns the linters which are part of the selected profile
+func profileLinter(linter []*LinterConfig, ids map[string]bool) []*LinterConfig {
+	filtered := make([]*LinterConfig, 0, len(linter))
+	for _, l := range linter {
+		if ids[l.id()] {
+			filtered = append(filtered, l)
+		} else {
+			log.WithFields("linter", l.id()).Debug("linter is not part of the selected profile")
+		}
+	}
+	return filtered
+}
+
+// mergeRawConfig deep merges two json objects
+// if any of both is no json object the child is returned
+func mergeRawConfig(parent, child json.RawMessage) json.RawMessage {
+	if parent == nil {
+		return child
+	}
+	if child == nil {
+		return parent
+	}
+
+	var parentVal, childVal interface{}
+	if err := json.Unmarshal(parent, &parentVal); err != nil {
+		return child
+	}
+	if err := json.Unmarshal(child, &childVal); err != nil {
+		return child
+	}
+
+	merged, err := json.Marshal(mergeValues(parentVal, childVal))
+	if err != nil {
+		return child
+	}
+
+	return merged
+}
+
+func mergeValues(parent, child interface{}) interface{} {
+	parentMap, parentIsMap := parent.(map[string]interface{})
+	childMap, childIsMap := child.(map[string]interface{})
+	if !parentIsMap || !childIsMap {
+		return child
+	}
+
+	merged := make(map[string]interface{}, len(parentMap)+len(childMap))
+	for k, v := range parentMap {
+		merged[k] = v
+	}
+	for k, v := range childMap {
+		merged[k] = mergeValues(parentMap[k], v)
+	}
+
+	return merged
+}
+
+// expand expands the values of c which differ from the ones of parent
+// and the lists of c which are not yet merged with the ones of parent
+func (c *Config) expand(parent *Config) error {
+	e := &expander{strict: c.StrictEnv}
+
+	if c.LinterInstallDirectory != parent.LinterInstallDirectory {
+		if err := e.expandAll(&c.LinterInstallDirectory); err != nil {
+			return err
+		}
+	}
+
+	for _, o := range c.Outputs {
+		if err := e.expandAll(&o.Path); err != nil {
+			return err
+		}
+	}
+
+	for i := range c.SkipDirs {
+		if err := e.expandAll(&c.SkipDirs[i]); err != nil {
+			return err
+		}
+	}
+
+	for i := range c.BuildTags {
+		if err := e.expandAll(&c.BuildTags[i]); err != nil {
+			return err
+		}
+	}
+
+	for _, buildCtx := range c.BuildContexts {
+		if err := e.expandAll(&buildCtx.GOOS, &buildCtx.GOARCH); err != nil {
+			return err
+		}
+		for i := range buildCtx.Tags {
+			if err := e.expandAll(&buildCtx.Tags[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := e.expandExclude(c.Exclude); err != nil {
+		return err
+	}
+
+	if err := e.expandGlobs(c.Generated.Paths); err != nil {
+		return err
+	}
+
+	if err := e.expandLinter(c.Linter); err != nil {
+		return err
+	}
+
+	for _, p := range c.Profiles {
+		if err := e.expandExclude(p.Exclude); err != nil {
+			return err
+		}
+		if err := e.expandLinter(p.Linter); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
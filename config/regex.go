@@ -38,3 +38,12 @@ func (r *Regex) UnmarshalText(data []byte) error {
 	r.Regexp, err = regexp.Compile(string(data))
 	return err
 }
+
+// MarshalText returns the source text of the regex
+func (r *Regex) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Regex) matchesOrUnset(input string) bool {
+	return r == nil || r.MatchString(input)
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// LinterSelection enables/disables linters by their name
+type LinterSelection struct {
+	// Enable linters which are disabled in the config
+	Enable map[string]bool
+
+	// Disable linters
+	Disable map[string]bool
+
+	// Only uses the specified linters, Enable/Disable are ignored
+	Only map[string]bool
+
+	// loaded are the names of the linters which were checked for selection
+	loaded map[string]bool
+}
+
+// NewLinterSelection constructs a LinterSelection of comma separated linter names
+func NewLinterSelection(enable, disable, only string) *LinterSelection {
+	return &LinterSelection{
+		Enable:  splitNames(enable),
+		Disable: splitNames(disable),
+		Only:    splitNames(only),
+		loaded:  map[string]bool{},
+	}
+}
+
+// MaySelect returns false if the linter is not selected regardless of its name
+// linters without a configured name have to be loaded to decide wether they are selected
+func (s *LinterSelection) MaySelect(l *LinterConfig) bool {
+	if l.Name != "" {
+		return s.Selects(l, l.Name)
+	}
+
+	if s == nil {
+		return !l.Disabled
+	}
+
+	if len(s.Only) > 0 {
+		return true
+	}
+
+	// disabled linters are loaded to verify the names of the disable flag as well
+	return !l.Disabled || len(s.Enable) > 0 || len(s.Disable) > 0
+}
+
+// Selects returns wether the linter with the provided name is selected
+func (s *LinterSelection) Selects(l *LinterConfig, name string) bool {
+	if s == nil {
+		return !l.Disabled
+	}
+
+	s.loaded[name] = true
+
+	if len(s.Only) > 0 {
+		return s.Only[name]
+	}
+
+	if s.Disable[name] {
+		return false
+	}
+
+	return !l.Disabled || s.Enable[name]
+}
+
+// Unknown returns the sorted names of the selection which matched
+// none of the linters checked for selection so far
+func (s *LinterSelection) Unknown() []string {
+	if s == nil {
+		return nil
+	}
+
+	unknownNames := map[string]bool{}
+	for _, names := range []map[string]bool{s.Enable, s.Disable, s.Only} {
+		for name := range names {
+			if !s.loaded[name] {
+				unknownNames[name] = true
+			}
+		}
+	}
+
+	unknown := make([]string, 0, len(unknownNames))
+	for name := range unknownNames {
+		unknown = append(unknown, name)
+	}
+
+	sort.Strings(unknown)
+	return unknown
+}
+
+func splitNames(names string) map[string]bool {
+	m := map[string]bool{}
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			m[name] = true
+		}
+	}
+	return m
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinterSelection(t *testing.T) {
+	t.Parallel()
+
+	named := &LinterConfig{Name: "golint"}
+	disabled := &LinterConfig{Name: "maligned", Disabled: true}
+	unnamed := &LinterConfig{Disabled: true}
+
+	var none *LinterSelection
+	assert.True(t, none.MaySelect(named))
+	assert.False(t, none.MaySelect(disabled))
+	assert.False(t, none.MaySelect(unnamed))
+
+	s := NewLinterSelection("maligned", "golint", "")
+	assert.False(t, s.MaySelect(named))
+	assert.True(t, s.MaySelect(disabled))
+	assert.True(t, s.MaySelect(unnamed))
+	assert.False(t, s.Selects(unnamed, "deadcode"))
+	assert.True(t, s.Selects(unnamed, "maligned"))
+
+	s = NewLinterSelection("", "", "deadcode, golint")
+	assert.True(t, s.MaySelect(named))
+	assert.False(t, s.MaySelect(disabled))
+	assert.True(t, s.MaySelect(unnamed))
+	assert.True(t, s.Selects(unnamed, "deadcode"))
+	assert.Empty(t, s.Unknown())
+
+	s = NewLinterSelection("golnt", "maligned", "")
+	assert.True(t, s.MaySelect(unnamed))
+	assert.True(t, s.MaySelect(named))
+	assert.False(t, s.MaySelect(disabled))
+	assert.Equal(t, []string{"golnt"}, s.Unknown())
+	assert.Nil(t, none.Unknown())
+}
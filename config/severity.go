@@ -17,3 +17,8 @@ func (s *Severity) UnmarshalText(data []byte) error {
 	s.Severity, err = api.ParseSeverity(strings.Title(string(data)))
 	return err
 }
+
+// MarshalText returns the lower case name of the severity
+func (s *Severity) MarshalText() ([]byte, error) {
+	return []byte(strings.ToLower(s.Severity.String())), nil
+}
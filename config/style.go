@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+)
+
+// OutputStyle is the style in which issues are written
+type OutputStyle string
+
+const (
+	// OutputStyleAuto uses the pretty style if stdout is a terminal
+	// and the template style otherwise
+	OutputStyleAuto OutputStyle = "auto"
+
+	// OutputStyleTemplate writes each issue using the OutputFormat template
+	OutputStyleTemplate OutputStyle = "template"
+
+	// OutputStylePretty writes the issues grouped by their files
+	// including the source line of each issue
+	OutputStylePretty OutputStyle = "pretty"
+
+	// OutputStyleJSON writes the issues as json array
+	OutputStyleJSON OutputStyle = "json"
+
+	// OutputStyleCheckstyle writes the issues as checkstyle xml report
+	OutputStyleCheckstyle OutputStyle = "checkstyle"
+
+	// OutputStyleSARIF writes the issues as SARIF 2.1.0 log
+	OutputStyleSARIF OutputStyle = "sarif"
+)
+
+const (
+	// OutputPathStdout is the output path which writes to stdout
+	OutputPathStdout = "stdout"
+
+	// OutputPathStderr is the output path which writes to stderr
+	OutputPathStderr = "stderr"
+)
+
+// UnmarshalText validates the output style
+func (s *OutputStyle) UnmarshalText(data []byte) error {
+	switch style := OutputStyle(data); style {
+	case OutputStyleAuto, OutputStyleTemplate, OutputStylePretty,
+		OutputStyleJSON, OutputStyleCheckstyle, OutputStyleSARIF:
+		*s = style
+		return nil
+	default:
+		return fmt.Errorf("%s is not a valid output style (auto, template, pretty, json, checkstyle, sarif)", data)
+	}
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"path/filepath"
+
+	"github.com/liut0/gomultilinter/internal/files"
+	"github.com/liut0/gomultilinter/internal/log"
+)
+
+// Tree resolves the config which applies to a directory
+// nested config files are merged with the config of their parent directory
+type Tree struct {
+	root    *Config
+	configs map[string]*Config
+}
+
+// NewTree constructs a new tree with the provided config as root
+func NewTree(root *Config) *Tree {
+	return &Tree{
+		root:    root,
+		configs: map[string]*Config{root.dir: root},
+	}
+}
+
+// Root returns the root config
+func (t *Tree) Root() *Config {
+	return t.root
+}
+
+// ForDir returns the config which applies to the provided directory
+// directories outside of the root config's directory get the root config
+func (t *Tree) ForDir(dir string) (*Config, error) {
+	dir = files.AbsPath(dir)
+	if conf, ok := t.configs[dir]; ok {
+		return conf, nil
+	}
+
+	if t.root.dir == "" || !files.ContainsPath(t.root.dir, dir) {
+		return t.root, nil
+	}
+
+	conf, err := t.ForDir(filepath.Dir(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	if path := filepath.Join(dir, configFileName); files.FileExists(path) {
+		log.WithFields("path", path).Debug("using nested config file")
+		if conf, err = conf.mergeNestedFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	t.configs[dir] = conf
+	return conf, nil
+}
+
+// Validate merges the nested config files of the directories
+// and returns the errors of the invalid ones
+// an error of a config file is only returned once, even if it applies to multiple directories
+func (t *Tree) Validate(dirs []string) []error {
+	var errs []error
+	reported := map[string]bool{}
+	for _, dir := range dirs {
+		if !files.FileExists(filepath.Join(dir, configFileName)) {
+			continue
+		}
+
+		if _, err := t.ForDir(dir); err != nil && !reported[err.Error()] {
+			reported[err.Error()] = true
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,235 @@
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liut0/gomultilinter/api"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testRootConfig = `
+min_severity: warning
+exclude:
+  names:
+    - 'root'
+linter:
+  - package: 'foo'
+    config:
+      a: 1
+      b:
+        c: 2
+`
+
+	testNestedConfig = `
+min_severity: error
+exclude:
+  names:
+    - 'nested'
+linter:
+  - package: 'foo'
+    config:
+      b:
+        d: 3
+  - package: 'bar'
+`
+)
+
+func writeTestConfig(t *testing.T, dir, content string) {
+	assert.NoError(t, os.MkdirAll(dir, os.ModePerm))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, configFileName), []byte(content), os.ModePerm))
+}
+
+func TestTreeForDir(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "gomultilinter")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeTestConfig(t, dir, testRootConfig)
+	writeTestConfig(t, filepath.Join(dir, "sub"), testNestedConfig)
+
+	root, err := ReadConfig(filepath.Join(dir, configFileName), false, false)
+	assert.NoError(t, err)
+
+	tree := NewTree(root)
+
+	conf, err := tree.ForDir(filepath.Join(dir, "pkg"))
+	assert.NoError(t, err)
+	assert.True(t, conf == root)
+
+	conf, err = tree.ForDir(filepath.Join(dir, "sub", "pkg"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "sub"), conf.Dir())
+	assert.Equal(t, api.SeverityError, conf.MinSeverity.Severity)
+	assert.Equal(t, api.SeverityWarning, root.MinSeverity.Severity)
+	assert.Len(t, conf.Exclude.Names, 2)
+	assert.Len(t, root.Exclude.Names, 1)
+
+	assert.Len(t, conf.Linter, 2)
+	assert.Equal(t, "bar", conf.Linter[1].Package)
+
+	var linterConf interface{}
+	assert.NoError(t, json.Unmarshal(conf.Linter[0].Config, &linterConf))
+	assert.Equal(t, map[string]interface{}{
+		"a": float64(1),
+		"b": map[string]interface{}{"c": float64(2), "d": float64(3)},
+	}, linterConf)
+
+	sameConf, err := tree.ForDir(filepath.Join(dir, "sub"))
+	assert.NoError(t, err)
+	assert.True(t, conf == sameConf)
+
+	conf, err = tree.ForDir(os.TempDir())
+	assert.NoError(t, err)
+	assert.True(t, conf == root)
+}
+
+func TestTreeForDirRootOnlyKeys(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "gomultilinter")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeTestConfig(t, dir, testRootConfig)
+	writeTestConfig(t, filepath.Join(dir, "sub"), `
+min_severity: error
+fail_on: warning
+exclude:
+  tests: true
+outputs:
+  - path: 'issues.json'
+`)
+
+	root, err := ReadConfig(filepath.Join(dir, configFileName), false, false)
+	assert.NoError(t, err)
+
+	_, err = NewTree(root).ForDir(filepath.Join(dir, "sub"))
+	assert.Error(t, err)
+
+	errs, ok := err.(Errors)
+	assert.True(t, ok)
+	assert.Len(t, errs, 3)
+	assert.Equal(t, filepath.Join(dir, "sub", configFileName)+":3:1: fail_on is only allowed in the root config file", errs[0].Error())
+	assert.Equal(t, filepath.Join(dir, "sub", configFileName)+":6:1: outputs is only allowed in the root config file", errs[1].Error())
+	assert.Equal(t, filepath.Join(dir, "sub", configFileName)+":5:3: exclude.tests is only allowed in the root config file", errs[2].Error())
+}
+
+func TestFindConfigFiles(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "gomultilinter")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeTestConfig(t, dir, "")
+	writeTestConfig(t, filepath.Join(dir, "repo"), "")
+	writeTestConfig(t, filepath.Join(dir, "repo", "a", "b"), "")
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "repo", ".git"), os.ModePerm))
+
+	paths, err := findConfigFiles(filepath.Join(dir, "repo", "a", "b"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "repo", configFileName),
+		filepath.Join(dir, "repo", "a", "b", configFileName),
+	}, paths)
+
+	writeTestConfig(t, filepath.Join(dir, "repo", "a"), "root: true")
+	paths, err = findConfigFiles(filepath.Join(dir, "repo", "a", "b"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "repo", "a", configFileName),
+		filepath.Join(dir, "repo", "a", "b", configFileName),
+	}, paths)
+
+	// the nearest config file above a repository without config files is used
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "home", "other", "repo", ".git"), os.ModePerm))
+	writeTestConfig(t, filepath.Join(dir, "home"), "")
+	writeTestConfig(t, filepath.Join(dir, "home", "other"), "")
+	paths, err = findConfigFiles(filepath.Join(dir, "home", "other", "repo"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "home", "other", configFileName)}, paths)
+}
+
+func TestTreeForDirExtends(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "gomultilinter")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "preset.yml"), []byte(`
+exclude:
+  names:
+    - 'preset'
+  rules:
+    - linter: 'golint'
+`), os.ModePerm))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "root-only.yml"), []byte(`
+sort_issues: true
+`), os.ModePerm))
+
+	writeTestConfig(t, dir, `
+extends:
+  - 'preset.yml'
+`)
+	writeTestConfig(t, filepath.Join(dir, "sub"), `
+extends:
+  - '../preset.yml'
+exclude:
+  names:
+    - 'sub'
+`)
+	writeTestConfig(t, filepath.Join(dir, "invalid"), `
+extends:
+  - '../root-only.yml'
+`)
+
+	root, err := ReadConfig(filepath.Join(dir, configFileName), false, false)
+	assert.NoError(t, err)
+	tree := NewTree(root)
+
+	// the preset is already merged into the root config
+	conf, err := tree.ForDir(filepath.Join(dir, "sub"))
+	assert.NoError(t, err)
+	assert.Len(t, conf.Exclude.Names, 2)
+	assert.Len(t, conf.Exclude.Rules, 1)
+
+	_, err = tree.ForDir(filepath.Join(dir, "invalid"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "sort_issues is only allowed in the root config file")
+}
+
+func TestTreeValidate(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "gomultilinter")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeTestConfig(t, dir, testRootConfig)
+	writeTestConfig(t, filepath.Join(dir, "valid"), testNestedConfig)
+	writeTestConfig(t, filepath.Join(dir, "invalid"), "fail_on: warning\n")
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "invalid", "pkg"), os.ModePerm))
+	writeTestConfig(t, filepath.Join(dir, "invalid", "pkg", "sub"), "")
+
+	root, err := ReadConfig(filepath.Join(dir, configFileName), false, false)
+	assert.NoError(t, err)
+
+	errs := NewTree(root).Validate([]string{
+		dir,
+		filepath.Join(dir, "valid"),
+		filepath.Join(dir, "invalid"),
+		filepath.Join(dir, "invalid", "pkg"),
+		filepath.Join(dir, "invalid", "pkg", "sub"),
+	})
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, filepath.Join(dir, "invalid", configFileName)+":1:1: fail_on is only allowed in the root config file", errs[0].Error())
+	}
+}
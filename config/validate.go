@@ -0,0 +1,401 @@
+package config
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+const (
+	yamlTagNull  = "!!null"
+	yamlTagBool  = "!!bool"
+	yamlTagInt   = "!!int"
+	yamlTagFloat = "!!float"
+)
+
+var (
+	// rootOnlyKeys are the keys which are only read from the root config
+	// and therefore rejected in nested config files
+	rootOnlyKeys = [][]string{
+		{"fail_on"},
+		{"sort_issues"},
+		{"max_issues"},
+		{"max_issues_per_linter"},
+		{"max_issues_per_file"},
+		{"max_same_issues"},
+		{"output_style"},
+		{"output_format"},
+		{"outputs"},
+		{"skip_dirs"},
+		{"gitignore"},
+		{"build_contexts"},
+		{"build_tags"},
+		{"profiles"},
+		{"exclude", "tests"},
+		{"exclude", "unused_rules"},
+	}
+
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	rawMessageType      = reflect.TypeOf(json.RawMessage{})
+	configType          = reflect.TypeOf(Config{})
+)
+
+// Error is an error at a position in a config file
+type Error struct {
+	Filename string
+	Line     int
+	Column   int
+	Msg      string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.Filename, e.Line, e.Column, e.Msg)
+}
+
+// Errors are all errors of a config file
+type Errors []*Error
+
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// keysValidator is implemented by config types which
+// require constraints between their keys
+type keysValidator interface {
+	validateKeys(values map[string]*yamlv3.Node) string
+}
+
+// configValidator validates the structure of a yaml config file
+// against the json tags of the config types
+//
+// the config is still decoded by ghodss/yaml (json tags, linter configs as json.RawMessage),
+// but it is based on yaml.v2 which does not expose the positions of the parsed values,
+// so the file is validated on the node tree of yaml.v3 which keeps line and column of each node
+type configValidator struct {
+	filename string
+	errs     Errors
+}
+
+// validateConfigFile returns an error containing the positions of unknown
+// fields, values of the wrong type and invalid values of the config file
+func validateConfigFile(filename string, content []byte) error {
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(content, &doc); err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+
+	v := &configValidator{filename: filename}
+	v.validate(&doc, configType, "")
+
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
+
+// validateNestedConfigFile returns an error containing the positions
+// of the keys of the nested config file which are only read from the root config
+func validateNestedConfigFile(filename string, content []byte) error {
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(content, &doc); err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	v := &configValidator{filename: filename}
+	for _, keys := range rootOnlyKeys {
+		node := doc.Content[0]
+		for _, key := range keys[:len(keys)-1] {
+			if node = mappingValue(node, key); node == nil {
+				break
+			}
+		}
+
+		if node == nil {
+			continue
+		}
+		if key := mappingKey(node, keys[len(keys)-1]); key != nil {
+			v.errorf(key, "%s is only allowed in the root config file", strings.Join(keys, "."))
+		}
+	}
+
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
+
+func (v *configValidator) errorf(node *yamlv3.Node, format string, args ...interface{}) {
+	v.errs = append(v.errs, &Error{
+		Filename: v.filename,
+		Line:     node.Line,
+		Column:   node.Column,
+		Msg:      fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *configValidator) validate(node *yamlv3.Node, t reflect.Type, path string) {
+	switch node.Kind {
+	case yamlv3.DocumentNode:
+		for _, n := range node.Content {
+			v.validate(n, t, path)
+		}
+		return
+	case yamlv3.AliasNode:
+		v.validate(node.Alias, t, path)
+		return
+	}
+
+	if node.Kind == yamlv3.ScalarNode && node.Tag == yamlTagNull {
+		return
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == rawMessageType:
+		return
+	case reflect.PtrTo(t).Implements(textUnmarshalerType):
+		if !v.expectKind(node, yamlv3.ScalarNode, path) {
+			return
+		}
+		if err := reflect.New(t).Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(node.Value)); err != nil {
+			v.errorf(node, "invalid value of %s: %v", path, err)
+		}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		v.validateStruct(node, t, path)
+	case reflect.Slice:
+		if v.expectKind(node, yamlv3.SequenceNode, path) {
+			for i, n := range node.Content {
+				v.validate(n, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	case reflect.Map:
+		if v.expectKind(node, yamlv3.MappingNode, path) {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				v.validate(node.Content[i+1], t.Elem(), joinPath(path, node.Content[i].Value))
+			}
+		}
+	case reflect.Bool:
+		v.expectScalar(node, path, "bool", yamlTagBool)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.expectScalar(node, path, "integer", yamlTagInt)
+	case reflect.Float32, reflect.Float64:
+		v.expectScalar(node, path, "number", yamlTagInt, yamlTagFloat)
+	case reflect.String:
+		v.expectKind(node, yamlv3.ScalarNode, path)
+	}
+}
+
+func (v *configValidator) validateStruct(node *yamlv3.Node, t reflect.Type, path string) {
+	if !v.expectKind(node, yamlv3.MappingNode, path) {
+		return
+	}
+
+	fields := jsonFields(t)
+	values := map[string]*yamlv3.Node{}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		fieldType, ok := fields[key.Value]
+		if !ok {
+			v.errorf(key, "unknown field %q in %s", key.Value, describePath(path))
+			continue
+		}
+
+		values[key.Value] = value
+		v.validate(value, fieldType, joinPath(path, key.Value))
+	}
+
+	if validator, ok := reflect.New(t).Interface().(keysValidator); ok {
+		if msg := validator.validateKeys(values); msg != "" {
+			v.errorf(node, "%s: %s", describePath(path), msg)
+		}
+	}
+}
+
+func (v *configValidator) expectKind(node *yamlv3.Node, kind yamlv3.Kind, path string) bool {
+	if node.Kind == kind {
+		return true
+	}
+
+	v.errorf(node, "%s has to be a %s", describePath(path), kindName(kind))
+	return false
+}
+
+func (v *configValidator) expectScalar(node *yamlv3.Node, path, typeName string, tags ...string) {
+	if node.Kind == yamlv3.ScalarNode {
+		for _, tag := range tags {
+			if node.Tag == tag {
+				return
+			}
+		}
+	}
+
+	v.errorf(node, "%s has to be a %s", describePath(path), typeName)
+}
+
+// jsonFields maps the json names of the struct's fields to their types
+// fields of embedded structs are inlined
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for name, fieldType := range jsonFields(field.Type) {
+				fields[name] = fieldType
+			}
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// sequenceLines returns the lines of the items of the sequence
+// at the path of mapping keys, nil if there is none
+func sequenceLines(content []byte, keys ...string) []int {
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(content, &doc); err != nil || len(doc.Content) == 0 {
+		return nil
+	}
+
+	node := doc.Content[0]
+	for _, key := range keys {
+		node = mappingValue(node, key)
+		if node == nil {
+			return nil
+		}
+	}
+
+	node = resolveAlias(node)
+	if node.Kind != yamlv3.SequenceNode {
+		return nil
+	}
+
+	lines := make([]int, 0, len(node.Content))
+	for _, item := range node.Content {
+		lines = append(lines, resolveAlias(item).Line)
+	}
+	return lines
+}
+
+func mappingValue(node *yamlv3.Node, key string) *yamlv3.Node {
+	node = resolveAlias(node)
+	if node.Kind != yamlv3.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func mappingKey(node *yamlv3.Node, key string) *yamlv3.Node {
+	node = resolveAlias(node)
+	if node.Kind != yamlv3.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i]
+		}
+	}
+	return nil
+}
+
+func resolveAlias(node *yamlv3.Node) *yamlv3.Node {
+	for node.Kind == yamlv3.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func describePath(path string) string {
+	if path == "" {
+		return "config"
+	}
+	return path
+}
+
+func kindName(kind yamlv3.Kind) string {
+	switch kind {
+	case yamlv3.MappingNode:
+		return "mapping"
+	case yamlv3.SequenceNode:
+		return "list"
+	default:
+		return "scalar value"
+	}
+}
+
+func nonEmpty(values map[string]*yamlv3.Node, key string) bool {
+	node, ok := values[key]
+	return ok && !(node.Kind == yamlv3.ScalarNode && (node.Tag == yamlTagNull || node.Value == ""))
+}
+
+func (*LinterConfig) validateKeys(values map[string]*yamlv3.Node) string {
+	if nonEmpty(values, "package") == nonEmpty(values, "plugin_path") {
+		return "exactly one of package or plugin_path has to be set"
+	}
+	return ""
+}
+
+func (*ExcludeRule) validateKeys(values map[string]*yamlv3.Node) string {
+	for _, key := range []string{"path", "linter", "category", "message", "severity"} {
+		if nonEmpty(values, key) {
+			return ""
+		}
+	}
+	return "at least one of path, linter, category, message or severity has to be set"
+}
+
+func (*SeverityRule) validateKeys(values map[string]*yamlv3.Node) string {
+	if !nonEmpty(values, "severity") {
+		return "severity has to be set"
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateConfigFile(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, validateConfigFile("valid.yml", []byte(`
+min_severity: warning
+exclude:
+  tests: true
+  names:
+    - 'foo'
+  rules:
+    - path: 'bar'
+      severity: info
+severity_rules:
+  - linter: 'errcheck'
+    severity: error
+linter:
+  - package: 'foo'
+    config:
+      anything:
+        - goes
+`)))
+
+	err := validateConfigFile("invalid.yml", []byte(`
+exlude:
+  tests: true
+min_severity: foo
+exclude:
+  tests: 'yes please'
+  names: 'foo'
+  rules:
+    - path: '('
+    - {}
+severity_rules:
+  - linter: 'errcheck'
+linter:
+  - package: 'foo'
+    plugin_path: 'foo.so'
+  - config: {}
+`))
+
+	assert.Error(t, err)
+
+	errs, ok := err.(Errors)
+	assert.True(t, ok)
+
+	var msgs []string
+	for _, e := range errs {
+		msgs = append(msgs, e.Error())
+	}
+
+	assert.Equal(t, []string{
+		`invalid.yml:2:1: unknown field "exlude" in config`,
+		`invalid.yml:4:15: invalid value of min_severity: Foo is not a valid Severity`,
+		`invalid.yml:6:10: exclude.tests has to be a bool`,
+		`invalid.yml:7:10: exclude.names has to be a list`,
+		"invalid.yml:9:13: invalid value of exclude.rules[0].path: error parsing regexp: missing closing ): `(`",
+		`invalid.yml:10:7: exclude.rules[1]: at least one of path, linter, category, message or severity has to be set`,
+		`invalid.yml:12:5: severity_rules[0]: severity has to be set`,
+		`invalid.yml:14:5: linter[0]: exactly one of package or plugin_path has to be set`,
+		`invalid.yml:16:5: linter[1]: exactly one of package or plugin_path has to be set`,
+	}, msgs)
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/liut0/gomultilinter/internal/checker/issue"
+	"github.com/liut0/gomultilinter/internal/log"
+	"github.com/liut0/gomultilinter/internal/report"
+)
+
+// writeHTMLReport writes the html report of the issues to the file
+// the sources of the files are read through sources
+func writeHTMLReport(path string, issues []*issue.LinterIssue, sources *issue.Sources) error {
+	f, err := os.Create(path)
+	if err != nil {
+		log.WithFields("err", err, "path", path).Debug("could not create html report")
+		return fmt.Errorf("could not create html report %v", err)
+	}
+
+	if err := report.WriteHTML(f, issues, sources); err != nil {
+		f.Close()
+		log.WithFields("err", err, "path", path).Debug("could not write html report")
+		return fmt.Errorf("could not write html report %v", err)
+	}
+
+	return f.Close()
+}
@@ -3,9 +3,15 @@ package checker
 
 import (
 	"fmt"
+	"go/build"
 	"go/parser"
 	"go/token"
 	"go/types"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
 
 	"context"
 
@@ -14,122 +20,331 @@ import (
 	"github.com/liut0/gomultilinter/internal/checker/filter"
 	"github.com/liut0/gomultilinter/internal/checker/imports"
 	"github.com/liut0/gomultilinter/internal/checker/issue"
+	"github.com/liut0/gomultilinter/internal/checker/overlay"
 	"github.com/liut0/gomultilinter/internal/log"
 	"golang.org/x/tools/go/loader"
 )
 
 const (
 	selfLinterName = "gomultilinter"
+	cgoEnabledEnv  = "CGO_ENABLED"
 )
 
+// LinterLoader loads the linters of a config
+type LinterLoader func(conf *config.Config) ([]api.Linter, error)
+
 // Checker is the coordinator/executor of the linting process
 type Checker struct {
-	excludeUnnecessaryNoLintDirectives bool
-	excludeTests                       bool
-	excludeNames                       config.MultiRegex
+	excludeUnusedRules bool
+	excludeTests       bool
+	sortIssues         bool
+	limits             *issueLimits
+
+	// streamIssues if true issues are written as soon as they are collected
+	// otherwise they are written at the end of Run to sort them
+	// or to deduplicate them across build contexts
+	streamIssues bool
+	writeLock    sync.Mutex
+
+	configs     *config.Tree
+	resolver    *imports.Resolver
+	loadLinter  LinterLoader
+	issueWriter IssueWriter
+	// sources reads the source lines of the written issues through the overlay
+	sources *issue.Sources
+
+	filters           *checkerFilters
+	selfIssueReporter api.IssueReporter
 
-	fileLinter map[string]api.FileLinter
-	pkgLinter  map[string]api.PackageLinter
+	scopes     []*scope
+	scopeIndex map[*config.Config]*scope
 
-	issueReporter           *IssueReporter
-	selfIssueReporter       api.IssueReporter
-	noLinterDirectiveFilter *filter.NoLinterDirectiveFilter
+	buildContexts []*build.Context
+	overlay       overlay.Overlay
+	programs      []*program
+	pkgScopes     map[*loader.PackageInfo]*scope
 
-	pkgs []*loader.PackageInfo
-	fset *token.FileSet
+	// buildContext is the name of the build context which is currently linted
+	// empty if only a single build context is linted
+	buildContext string
+
+	analysedPkgs  map[string]bool
+	analysedFiles map[string]bool
+	linterErrors  map[string]int
+	linterPanics  map[string]int
+	hiddenIssues  int
+
+	// metrics is nil if metrics are disabled
+	metrics *metricsCollector
 
 	ctx context.Context
 }
 
+// program are the packages loaded in a build context
+type program struct {
+	buildContext string
+	pkgs         []*loader.PackageInfo
+	fset         *token.FileSet
+}
+
 // NewChecker constructs a new checker according to the provided arguments
-func NewChecker(conf *config.Config, linter []api.Linter) (*Checker, error) {
-	issueWriter, err := newConsoleWriter(conf.OutputFormat)
+// linter are the linters of conf, loadLinter is used to load the linters
+// of nested config files
+func NewChecker(conf *config.Config, linter []api.Linter, loadLinter LinterLoader) (*Checker, error) {
+	sources := issue.NewSources(nil)
+	issueWriter, err := newIssueWriter(conf, sources)
 	if err != nil {
 		return nil, err
 	}
 
-	noLinterDirectiveFilter := &filter.NoLinterDirectiveFilter{}
+	buildCtxs := buildContexts(conf)
 
-	reporter := &IssueReporter{
-		issueWriter: issueWriter,
+	c := &Checker{
+		excludeUnusedRules: conf.Exclude.UnusedRules,
+		excludeTests:       conf.Exclude.Tests,
+		sortIssues:         conf.SortIssues,
+		limits:             newIssueLimits(conf),
+		streamIssues:       !conf.SortIssues && len(buildCtxs) == 1,
 
-		filter: filter.ChainFilter(
-			filter.SeverityFilter(conf.MinSeverity.Severity),
-			filter.CategoryFilter(conf.Exclude.Categories),
-			// filter names again (pkglinters cant filter filenames before linting)
-			filter.FilenameFilter(conf.Exclude.Names),
-			filter.MessageFilter(conf.Exclude.Messages),
-			noLinterDirectiveFilter),
-	}
+		configs: config.NewTree(conf),
+		resolver: &imports.Resolver{
+			SkipDirs:  conf.SkipDirs,
+			GitIgnore: conf.GitIgnore,
+		},
+		loadLinter:  loadLinter,
+		issueWriter: issueWriter,
+		sources:     sources,
 
-	c := &Checker{
-		excludeUnnecessaryNoLintDirectives: conf.Exclude.UnnecessaryNoLintDirectives,
-		excludeTests:                       conf.Exclude.Tests,
-		excludeNames:                       conf.Exclude.Names,
+		filters: &checkerFilters{
+			excludeRules: filter.NewExcludeRulesFilter(),
+			targetFiles:  filter.NewTargetFilesFilter(),
+			suppressions: filter.NewSuppressionCounter(),
+		},
 
-		fileLinter: map[string]api.FileLinter{},
-		pkgLinter:  map[string]api.PackageLinter{},
+		scopeIndex:    map[*config.Config]*scope{},
+		buildContexts: buildCtxs,
+		pkgScopes:     map[*loader.PackageInfo]*scope{},
 
-		issueReporter:           reporter,
-		selfIssueReporter:       reporter.entry(selfLinterName),
-		noLinterDirectiveFilter: noLinterDirectiveFilter,
+		analysedPkgs:  map[string]bool{},
+		analysedFiles: map[string]bool{},
+		linterErrors:  map[string]int{},
+		linterPanics:  map[string]int{},
 
 		ctx: context.Background(),
 	}
 
-	for _, l := range linter {
-		switch lT := l.(type) {
-		case api.FileLinter:
-			c.fileLinter[lT.Name()] = lT
-		case api.PackageLinter:
-			c.pkgLinter[lT.Name()] = lT
-		default:
-			log.WithFields("linter", l.Name()).Debug("unsupported linter")
-			return nil, fmt.Errorf("unsupported linter %v", l.Name())
-		}
+	rootScope, err := c.addScope(conf, linter)
+	if err != nil {
+		return nil, err
 	}
+	c.selfIssueReporter = rootScope.selfIssueReporter
 
 	return c, nil
 }
 
-// Load loads/parses the specified paths
-// see imports.ResolvePaths how paths are resolved
+// EnableMetrics enables the collection of performance metrics of the linters
+func (c *Checker) EnableMetrics() {
+	c.metrics = newMetricsCollector()
+}
+
+// Metrics returns the performance metrics of the linters
+// including the slowest n packages per linter (n <= 0 means all)
+// returns nil if metrics are not enabled
+func (c *Checker) Metrics(n int) *Metrics {
+	if c.metrics == nil {
+		return nil
+	}
+	return c.metrics.metrics(n)
+}
+
+// SetOverlay sets the overlay whose files replace the ones on the disk
+// when loading the packages, issues are reported against the real paths
+func (c *Checker) SetOverlay(o overlay.Overlay) {
+	c.overlay = o
+	c.resolver.Overlay = o
+	c.sources.SetOverlay(o)
+}
+
+// Sources returns the reader of the source lines of the issues
+// which reads the files through the overlay
+func (c *Checker) Sources() *issue.Sources {
+	return c.sources
+}
+
+// Load loads/parses the specified paths in all build contexts
+// see imports.Resolver.ResolvePaths how paths are resolved
 func (c *Checker) Load(paths ...string) error {
 	log.WithFields("pahts", paths).Debug("loading paths")
-	importPaths, err := imports.ResolvePaths(paths...)
+
+	for _, buildCtx := range c.buildContexts {
+		prog, err := c.loadBuildContext(c.overlay.Context(buildCtx), paths)
+		if err != nil {
+			return err
+		}
+		if prog != nil {
+			c.programs = append(c.programs, prog)
+		}
+	}
+
+	if len(c.programs) == 0 {
+		log.WithFields("pahts", paths).Debug("no packages in any build context")
+		return fmt.Errorf("could not load pkgs: no packages found for %v", paths)
+	}
+
+	return nil
+}
+
+// loadBuildContext loads the paths in the build context
+// returns nil if multiple build contexts are linted and
+// the paths contain no packages in this one
+func (c *Checker) loadBuildContext(buildCtx *build.Context, paths []string) (*program, error) {
+	prog := &program{}
+	if len(c.buildContexts) > 1 {
+		prog.buildContext = buildContextName(buildCtx)
+	}
+
+	log.WithFields("build_context", buildContextName(buildCtx)).Debug("loading build context")
+	c.resolver.Context = buildCtx
+	targets, err := c.resolver.ResolvePaths(paths...)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	c.filters.targetFiles.AddFiles(targets.Files...)
+
+	if len(targets.Pkgs) == 0 && len(c.buildContexts) > 1 {
+		log.WithFields("build_context", prog.buildContext).Debug("no packages in build context")
+		return nil, nil
 	}
 
-	program, err := c.load(importPaths)
+	loaded, err := c.load(buildCtx, targets.Pkgs)
 	if err != nil {
-		log.WithFields("err", err).Debug("could not load pkgs")
-		return fmt.Errorf("could not load pkgs %v", err)
+		log.WithFields("err", err, "build_context", buildContextName(buildCtx)).Debug("could not load pkgs")
+		return nil, fmt.Errorf("could not load pkgs %v", err)
 	}
 
-	c.pkgs = program.InitialPackages()
-	c.fset = program.Fset
-	return nil
+	prog.pkgs = loaded.InitialPackages()
+	prog.fset = loaded.Fset
+
+	for _, pkg := range prog.pkgs {
+		s, err := c.scopeOf(pkg, prog.fset)
+		if err != nil {
+			return nil, err
+		}
+		c.pkgScopes[pkg] = s
+	}
+
+	return prog, nil
 }
 
 // Run runs the checker on the loaded paths
-func (c *Checker) Run() []*issue.LinterIssue {
+// all issues are returned, even the ones hidden by the limits
+// the error is returned if the issues could not be written to the outputs
+func (c *Checker) Run() ([]*issue.LinterIssue, error) {
 	log.Debug("running linters")
 
-	c.walkPkgs(c.pkgs, c.fset)
+	for _, prog := range c.programs {
+		c.buildContext = prog.buildContext
+		c.walkPkgs(prog.pkgs, prog.fset)
+	}
+	c.buildContext = ""
 
-	if !c.excludeUnnecessaryNoLintDirectives {
-		c.noLinterDirectiveFilter.ReportUnnecessaryDirectives(c.selfIssueReporter)
+	for _, s := range c.scopes {
+		if !s.excludeUnnecessaryNoLintDirectives {
+			s.noLinterDirectiveFilter.ReportUnnecessaryDirectives(s.selfIssueReporter)
+		}
 	}
 
-	return c.issueReporter.allIssues
+	if !c.excludeUnusedRules {
+		c.filters.excludeRules.ReportUnusedRules(c.selfIssueReporter)
+	}
+
+	var issues []*issue.LinterIssue
+	for _, s := range c.scopes {
+		issues = append(issues, s.issueReporter.allIssues...)
+	}
+
+	if !c.streamIssues {
+		if c.sortIssues {
+			issue.Sort(issues)
+			issues = issue.Deduplicate(issues)
+		}
+
+		for _, iss := range issues {
+			c.writeIssue(iss)
+		}
+	}
+
+	if err := c.issueWriter.Close(); err != nil {
+		log.WithFields("err", err).Debug("could not write issues")
+		return issues, fmt.Errorf("could not write issues %v", err)
+	}
+
+	return issues, nil
 }
 
-func (c *Checker) load(paths []string) (*loader.Program, error) {
+// writeIssue writes the issue if it is within the limits
+// otherwise it is counted as hidden
+func (c *Checker) writeIssue(iss *issue.LinterIssue) {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	if !c.limits.allow(iss) {
+		c.hiddenIssues++
+		return
+	}
+	c.issueWriter.Write(iss)
+}
+
+func (c *Checker) addScope(conf *config.Config, linter []api.Linter) (*scope, error) {
+	var write func(*issue.LinterIssue)
+	if c.streamIssues {
+		write = c.writeIssue
+	}
+
+	s, err := newScope(conf, linter, c.filters, write)
+	if err != nil {
+		return nil, err
+	}
+
+	c.scopes = append(c.scopes, s)
+	c.scopeIndex[conf] = s
+	return s, nil
+}
+
+// scopeOf returns the scope of the config which applies to the pkg's directory
+// the linters of nested configs are loaded on first use
+func (c *Checker) scopeOf(pkg *loader.PackageInfo, fset *token.FileSet) (*scope, error) {
+	if len(pkg.Files) == 0 {
+		return c.scopes[0], nil
+	}
+
+	dir := filepath.Dir(fset.Position(pkg.Files[0].Pos()).Filename)
+	conf, err := c.configs.ForDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if s, ok := c.scopeIndex[conf]; ok {
+		return s, nil
+	}
+
+	log.WithFields("dir", conf.Dir(), "pkg", pkg.Pkg.Path()).Debug("loading linters of nested config")
+	linter, err := c.loadLinter(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.addScope(conf, linter)
+}
+
+func (c *Checker) load(buildCtx *build.Context, paths []string) (*loader.Program, error) {
 	loadCfg := loader.Config{
+		Build: buildCtx,
 		TypeChecker: types.Config{
-			// ignore type checker errors
-			Error: func(error) {},
+			// type errors of the packages are reported while walking them
+			Error: func(err error) {
+				log.WithFields("err", err).Debug("type error")
+			},
 		},
 		AllowErrors: true,
 		ParserMode:  parser.ParseComments,
@@ -144,3 +359,44 @@ func (c *Checker) load(paths []string) (*loader.Program, error) {
 
 	return loadCfg.Load()
 }
+
+// buildContexts returns the go/build contexts of the config's build contexts
+// the config's build tags are added to each context
+// cgo is disabled in contexts of other platforms than the current one
+// if no build contexts are configured the default context is used
+func buildContexts(conf *config.Config) []*build.Context {
+	confCtxs := conf.BuildContexts
+	if len(confCtxs) == 0 {
+		confCtxs = []*config.BuildContext{{}}
+	}
+
+	buildCtxs := make([]*build.Context, 0, len(confCtxs))
+	for _, confCtx := range confCtxs {
+		buildCtx := build.Default
+		if confCtx.GOOS != "" {
+			buildCtx.GOOS = confCtx.GOOS
+		}
+		if confCtx.GOARCH != "" {
+			buildCtx.GOARCH = confCtx.GOARCH
+		}
+		buildCtx.BuildTags = append(append(append([]string{}, buildCtx.BuildTags...), confCtx.Tags...), conf.BuildTags...)
+
+		// like go build, cgo is disabled when cross compiling unless explicitly enabled
+		if (buildCtx.GOOS != runtime.GOOS || buildCtx.GOARCH != runtime.GOARCH) && os.Getenv(cgoEnabledEnv) != "1" {
+			buildCtx.CgoEnabled = false
+		}
+
+		buildCtxs = append(buildCtxs, &buildCtx)
+	}
+	return buildCtxs
+}
+
+// buildContextName returns a short name of the build context
+// e.g. linux/amd64 or linux/amd64:integration,foo
+func buildContextName(buildCtx *build.Context) string {
+	name := buildCtx.GOOS + "/" + buildCtx.GOARCH
+	if len(buildCtx.BuildTags) > 0 {
+		name += ":" + strings.Join(buildCtx.BuildTags, ",")
+	}
+	return name
+}
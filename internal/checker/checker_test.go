@@ -0,0 +1,53 @@
+package checker
+
+import (
+	"errors"
+	"go/build"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/liut0/gomultilinter/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildContexts(t *testing.T) {
+	t.Parallel()
+
+	otherOS := "windows"
+	if runtime.GOOS == otherOS {
+		otherOS = "linux"
+	}
+
+	ctxs := buildContexts(&config.Config{
+		BuildContexts: []*config.BuildContext{
+			{Tags: []string{"integration"}},
+			{GOOS: otherOS, GOARCH: "amd64"},
+		},
+		BuildTags: []string{"foo"},
+	})
+
+	assert.Len(t, ctxs, 2)
+	assert.Equal(t, runtime.GOOS, ctxs[0].GOOS)
+	assert.Equal(t, []string{"integration", "foo"}, ctxs[0].BuildTags)
+	assert.Equal(t, build.Default.CgoEnabled, ctxs[0].CgoEnabled)
+
+	assert.Equal(t, otherOS, ctxs[1].GOOS)
+	assert.Equal(t, []string{"foo"}, ctxs[1].BuildTags)
+	if os.Getenv(cgoEnabledEnv) != "1" {
+		assert.False(t, ctxs[1].CgoEnabled)
+	}
+}
+
+func TestCheckerRunWriteError(t *testing.T) {
+	t.Parallel()
+
+	c := &Checker{
+		excludeUnusedRules: true,
+		limits:             &issueLimits{},
+		issueWriter:        &recordingWriter{closeErr: errors.New("disk full")},
+	}
+
+	_, err := c.Run()
+	assert.Error(t, err)
+}
@@ -2,6 +2,7 @@ package checker
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"text/template"
 
@@ -11,8 +12,10 @@ import (
 )
 
 // IssueWriter writes an issue to a target
+// Close is called after all issues were written
 type IssueWriter interface {
 	Write(issue *issue.LinterIssue)
+	Close() error
 }
 
 // FileWriter implements the IssueWriter interface
@@ -20,11 +23,33 @@ type IssueWriter interface {
 // to the provided files
 type FileWriter struct {
 	outTemplate *template.Template
-	out         map[api.Severity]*os.File
+	out         map[api.Severity]io.Writer
+	sources     *issue.Sources
 }
 
-func newConsoleWriter(tmplStr string) (*FileWriter, error) {
-	tmpl, err := template.New("").Parse(tmplStr)
+func newConsoleWriter(tmplStr string, sources *issue.Sources) (*FileWriter, error) {
+	return newFileWriter(tmplStr, sources, map[api.Severity]io.Writer{
+		api.SeverityInfo:    os.Stdout,
+		api.SeverityWarning: os.Stderr,
+		api.SeverityError:   os.Stderr,
+	})
+}
+
+func newTemplateWriter(tmplStr string, out io.Writer, sources *issue.Sources) (*FileWriter, error) {
+	return newFileWriter(tmplStr, sources, map[api.Severity]io.Writer{
+		api.SeverityInfo:    out,
+		api.SeverityWarning: out,
+		api.SeverityError:   out,
+	})
+}
+
+func newFileWriter(tmplStr string, sources *issue.Sources, out map[api.Severity]io.Writer) (*FileWriter, error) {
+	outs := make([]io.Writer, 0, len(out))
+	for _, o := range out {
+		outs = append(outs, o)
+	}
+
+	tmpl, err := template.New("").Funcs(templateFuncs(colorsEnabled(outs...), sources)).Parse(tmplStr)
 	if err != nil {
 		log.WithFields("err", err).Debug("could not parse output template")
 		return nil, fmt.Errorf("could not parse output template %v", err)
@@ -32,20 +57,22 @@ func newConsoleWriter(tmplStr string) (*FileWriter, error) {
 
 	return &FileWriter{
 		outTemplate: tmpl,
-		out: map[api.Severity]*os.File{
-			api.SeverityInfo:    os.Stdout,
-			api.SeverityWarning: os.Stderr,
-			api.SeverityError:   os.Stderr,
-		},
+		out:         out,
+		sources:     sources,
 	}, nil
 }
 
 func (w *FileWriter) Write(issue *issue.LinterIssue) {
 	out, ok := w.out[issue.Severity]
 	if ok {
-		if err := w.outTemplate.Execute(out, issue); err != nil {
+		if err := w.outTemplate.Execute(out, &templateIssue{LinterIssue: issue, sources: w.sources}); err != nil {
 			log.WithFields("err", err).Error("output template execution failed")
 		}
 		fmt.Fprintln(out)
 	}
 }
+
+// Close does nothing, the files are owned by the caller
+func (w *FileWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,46 @@
+package filter
+
+import (
+	"sync"
+
+	"github.com/liut0/gomultilinter/internal/checker/issue"
+)
+
+// SuppressionCounter counts the issues which are filtered out per filter
+type SuppressionCounter struct {
+	lock   sync.Mutex
+	counts map[string]int
+}
+
+// NewSuppressionCounter constructs a new SuppressionCounter
+func NewSuppressionCounter() *SuppressionCounter {
+	return &SuppressionCounter{
+		counts: map[string]int{},
+	}
+}
+
+// Counted returns an IssueFilter which counts the issues filtered out by f as name
+func (c *SuppressionCounter) Counted(name string, f IssueFilter) IssueFilter {
+	return IssueFilterFunc(func(issue *issue.LinterIssue) bool {
+		if !f.IgnoreIssue(issue) {
+			return false
+		}
+
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		c.counts[name]++
+		return true
+	})
+}
+
+// Counts returns the number of filtered out issues per filter
+func (c *SuppressionCounter) Counts() map[string]int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	counts := make(map[string]int, len(c.counts))
+	for name, count := range c.counts {
+		counts[name] = count
+	}
+	return counts
+}
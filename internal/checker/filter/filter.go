@@ -63,3 +63,11 @@ func FilenameFilter(exclude config.MultiRegex) IssueFilter {
 		return exclude.MatchesAny(issue.Path.Abs)
 	})
 }
+
+// PathFilter returns an IssueFilter which filters out issues
+// with a path matching any of the provided glob patterns
+func PathFilter(exclude config.MultiGlob) IssueFilter {
+	return IssueFilterFunc(func(issue *issue.LinterIssue) bool {
+		return exclude.MatchesAny(issue.Path.Abs)
+	})
+}
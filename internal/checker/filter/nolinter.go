@@ -1,6 +1,7 @@
 package filter
 
 import (
+	"fmt"
 	"go/ast"
 	"go/token"
 	"strings"
@@ -11,17 +12,21 @@ import (
 )
 
 const (
-	noLinterRgxGrpLinter  = "LINTER"
-	noLinterRgxLinterName = `[A-Za-z0-9_\-]+`
+	noLinterRgxGrpTargets = "TARGETS"
+	noLinterRgxName       = `[A-Za-z0-9_\-]+`
+	noLinterRgxTarget     = noLinterRgxName + `(/` + noLinterRgxName + `)?`
+
+	noLinterTargetSeparator = "/"
 
 	categoryUnnecessaryNoLinterDirective = "unnecessary-nolinter-directive"
 	msgUnnecessaryNoLinterDirective      = "unnecessary nolinter directive detected"
+	msgUnnecessaryNoLinterTargets        = "unnecessary nolinter directive targets detected: %s"
 )
 
 var (
 	noLinterRgx = regex.MustCompile(
 		`// ?nolint(: )?` +
-			`(?P<` + noLinterRgxGrpLinter + `>(` + noLinterRgxLinterName + `)(, ?` + noLinterRgxLinterName + `)*)?`)
+			`(?P<` + noLinterRgxGrpTargets + `>(` + noLinterRgxTarget + `)(, ?` + noLinterRgxTarget + `)*)?`)
 )
 
 // NoLinterDirectiveFilter filters out issues to which a nolinter directive applies
@@ -35,8 +40,7 @@ type NoLinterDirectiveFilter struct {
 }
 
 type noLinterRange struct {
-	linters      map[string]bool
-	filterLinter bool
+	targets []*noLinterTarget
 
 	start token.Position
 	end   token.Position
@@ -46,8 +50,27 @@ type noLinterRange struct {
 	necessary bool
 }
 
+// noLinterTarget is a single target of a nolint directive
+// either a linter name or a linter name followed by a category
+// separated by a slash e.g. golint/comments
+type noLinterTarget struct {
+	linter   string
+	category string
+
+	necessary bool
+}
+
 // AddFile indexes all nolint directives in this file
+// files which were already added (e.g. in another build context) are skipped
 func (f *NoLinterDirectiveFilter) AddFile(file *api.File) {
+	if f.parsedFiles[file.Position.Filename] {
+		return
+	}
+	if f.parsedFiles == nil {
+		f.parsedFiles = map[string]bool{}
+	}
+	f.parsedFiles[file.Position.Filename] = true
+
 	for node, cmntGrps := range file.CommentMap {
 		for _, cmntGrp := range cmntGrps {
 			for _, cmnt := range cmntGrp.List {
@@ -63,24 +86,32 @@ func (f *NoLinterDirectiveFilter) parseNoLinterRange(cmnt *ast.Comment, file *ap
 		return
 	}
 
-	linters := map[string]bool{}
+	var targets []*noLinterTarget
 
-	linterNames, ok := match[noLinterRgxGrpLinter]
+	targetNames, ok := match[noLinterRgxGrpTargets]
 	if ok {
-		for _, linterName := range strings.Split(linterNames, ",") {
-			linters[strings.TrimSpace(linterName)] = true
+		for _, targetName := range strings.Split(targetNames, ",") {
+			targets = append(targets, parseNoLinterTarget(strings.TrimSpace(targetName)))
 		}
 	}
 
 	f.ranges = append(f.ranges, &noLinterRange{
-		path:         file.Position.Filename,
-		start:        file.FSet.Position(affectedNode.Pos()),
-		end:          file.FSet.Position(affectedNode.End()),
-		linters:      linters,
-		filterLinter: len(linters) > 0,
+		path:    file.Position.Filename,
+		start:   file.FSet.Position(affectedNode.Pos()),
+		end:     file.FSet.Position(affectedNode.End()),
+		targets: targets,
 	})
 }
 
+func parseNoLinterTarget(name string) *noLinterTarget {
+	parts := strings.SplitN(name, noLinterTargetSeparator, 2)
+	target := &noLinterTarget{linter: parts[0]}
+	if len(parts) == 2 {
+		target.category = parts[1]
+	}
+	return target
+}
+
 // IgnoreIssue returns wether this issue should be ignored (true) or written out (false)
 func (f *NoLinterDirectiveFilter) IgnoreIssue(issue *issue.LinterIssue) bool {
 	if f.disabled {
@@ -106,6 +137,16 @@ func (f *NoLinterDirectiveFilter) ReportUnnecessaryDirectives(reporter api.Issue
 				Category: categoryUnnecessaryNoLinterDirective,
 				Message:  msgUnnecessaryNoLinterDirective,
 			})
+			continue
+		}
+
+		if unused := r.unusedTargets(); len(unused) > 0 {
+			reporter.Report(&api.Issue{
+				Position: r.start,
+				Severity: api.SeverityWarning,
+				Category: categoryUnnecessaryNoLinterDirective,
+				Message:  fmt.Sprintf(msgUnnecessaryNoLinterTargets, strings.Join(unused, ", ")),
+			})
 		}
 	}
 	f.disabled = false
@@ -116,10 +157,43 @@ func (r *noLinterRange) includes(issue *issue.LinterIssue) bool {
 		return false
 	}
 
-	if !r.filterLinter || r.linters[issue.Linter] {
+	if len(r.targets) == 0 {
 		r.necessary = true
 		return true
 	}
 
-	return false
+	var included bool
+	for _, target := range r.targets {
+		if target.matches(issue) {
+			target.necessary = true
+			included = true
+		}
+	}
+
+	if included {
+		r.necessary = true
+	}
+
+	return included
+}
+
+func (r *noLinterRange) unusedTargets() []string {
+	var unused []string
+	for _, target := range r.targets {
+		if !target.necessary {
+			unused = append(unused, target.String())
+		}
+	}
+	return unused
+}
+
+func (t *noLinterTarget) matches(issue *issue.LinterIssue) bool {
+	return t.linter == issue.Linter && (t.category == "" || t.category == issue.Category)
+}
+
+func (t *noLinterTarget) String() string {
+	if t.category == "" {
+		return t.linter
+	}
+	return t.linter + noLinterTargetSeparator + t.category
 }
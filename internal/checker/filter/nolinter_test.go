@@ -78,9 +78,73 @@ func TestNoLinterDirectiveFilter(t *testing.T) {
 	iss.Linter = "noLinter"
 	assert.False(t, filter.IgnoreIssue(iss))
 
+	r := &testReporter{}
+	filter.ReportUnnecessaryDirectives(r)
+	assert.Len(t, r.issues, 2)
+	messages := map[int]string{}
+	for _, iss := range r.issues {
+		assert.Equal(t, fpath, iss.Position.Filename)
+		messages[iss.Position.Line] = iss.Message
+	}
+	assert.Equal(t, map[int]string{
+		10: "unnecessary nolinter directive targets detected: barlinter",
+		14: "unnecessary nolinter directive detected",
+	}, messages)
+}
+
+const testSrcTargets = `package p
+
+// nolint: foolinter/comments, barlinter
+func foo() {
+}
+
+// nolint: foolinter/cyclo, foolinter/comments
+func bar() {
+}`
+
+func TestNoLinterDirectiveFilterTargets(t *testing.T) {
+	t.Parallel()
+
+	fpath := files.AbsPath("targets.go")
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, fpath, testSrcTargets, parser.ParseComments)
+	assert.NoError(t, err)
+
+	filter := &NoLinterDirectiveFilter{}
+
+	pos := fset.Position(file.Pos())
+	filter.AddFile(&api.File{
+		Package: &api.Package{
+			FSet: fset,
+		},
+		Position:   &pos,
+		CommentMap: ast.NewCommentMap(fset, file, file.Comments),
+		ASTFile:    file,
+	})
+
+	iss := issue.ToLinterIssue(&api.Issue{
+		Category: "comments",
+		Position: token.Position{
+			Filename: "targets.go",
+			Line:     4,
+		},
+	}, "foolinter")
+
+	assert.True(t, filter.IgnoreIssue(iss))
+
+	iss.Category = "cyclo"
+	assert.False(t, filter.IgnoreIssue(iss))
+
+	iss.Linter = "barlinter"
+	assert.True(t, filter.IgnoreIssue(iss))
+
+	iss.Position.Line = 8
+	iss.Linter = "foolinter"
+	assert.True(t, filter.IgnoreIssue(iss))
+
 	r := &testReporter{}
 	filter.ReportUnnecessaryDirectives(r)
 	assert.Len(t, r.issues, 1)
-	assert.Equal(t, 14, r.issues[0].Position.Line)
-	assert.Equal(t, fpath, r.issues[0].Position.Filename)
+	assert.Equal(t, 8, r.issues[0].Position.Line)
+	assert.Equal(t, "unnecessary nolinter directive targets detected: foolinter/comments", r.issues[0].Message)
 }
@@ -0,0 +1,98 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/liut0/gomultilinter/api"
+	"github.com/liut0/gomultilinter/config"
+	"github.com/liut0/gomultilinter/internal/checker/issue"
+)
+
+const (
+	categoryUnusedExcludeRule = "unused-exclude-rule"
+	msgUnusedExcludeRule      = "exclude rule never matched: %s"
+)
+
+// ExcludeRulesFilter provides filters which filter out issues matching any of the
+// configured exclude rules and keeps track of the rules which never matched
+// rules shared by multiple filters are only reported once
+type ExcludeRulesFilter struct {
+	disabled bool
+
+	rules   []*excludeRule
+	indexed map[*config.ExcludeRule]*excludeRule
+}
+
+type excludeRule struct {
+	*config.ExcludeRule
+
+	necessary bool
+}
+
+// NewExcludeRulesFilter constructs a new ExcludeRulesFilter
+func NewExcludeRulesFilter() *ExcludeRulesFilter {
+	return &ExcludeRulesFilter{
+		indexed: map[*config.ExcludeRule]*excludeRule{},
+	}
+}
+
+// Filter returns an IssueFilter which filters out issues
+// matching any of the provided rules
+func (f *ExcludeRulesFilter) Filter(rules []*config.ExcludeRule) IssueFilter {
+	excludeRules := make([]*excludeRule, 0, len(rules))
+	for _, rule := range rules {
+		r, ok := f.indexed[rule]
+		if !ok {
+			r = &excludeRule{ExcludeRule: rule}
+			f.indexed[rule] = r
+			f.rules = append(f.rules, r)
+		}
+		excludeRules = append(excludeRules, r)
+	}
+
+	return IssueFilterFunc(func(issue *issue.LinterIssue) bool {
+		if f.disabled {
+			return false
+		}
+
+		var ignore bool
+		for _, r := range excludeRules {
+			if r.matches(issue) {
+				r.necessary = true
+				ignore = true
+			}
+		}
+		return ignore
+	})
+}
+
+// ReportUnusedRules reports exclude rules which never matched an issue
+func (f *ExcludeRulesFilter) ReportUnusedRules(reporter api.IssueReporter) {
+	f.disabled = true
+	for _, r := range f.rules {
+		if !r.necessary {
+			reporter.Report(&api.Issue{
+				Position: r.Position(),
+				Severity: api.SeverityWarning,
+				Category: categoryUnusedExcludeRule,
+				Message:  fmt.Sprintf(msgUnusedExcludeRule, r),
+			})
+		}
+	}
+	f.disabled = false
+}
+
+func (r *excludeRule) matches(issue *issue.LinterIssue) bool {
+	return r.IssueMatcher.Matches(issue.Path.Abs, issue.Linter, issue.Category, issue.Message) &&
+		(r.Severity == nil || r.Severity.Severity == issue.Severity)
+}
+
+func (r *excludeRule) String() string {
+	matchers := r.IssueMatcher.String()
+	if r.Severity == nil {
+		return matchers
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%s severity=%q", matchers, r.Severity.String()))
+}
@@ -0,0 +1,58 @@
+package filter
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/liut0/gomultilinter/api"
+	"github.com/liut0/gomultilinter/config"
+	"github.com/liut0/gomultilinter/internal/checker/issue"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExcludeRulesFilter(t *testing.T) {
+	t.Parallel()
+
+	rules := NewExcludeRulesFilter()
+	f := rules.Filter([]*config.ExcludeRule{
+		{
+			IssueMatcher: config.IssueMatcher{
+				Path:   &config.Regex{Regexp: regexp.MustCompile(`_test\.go$`)},
+				Linter: &config.Regex{Regexp: regexp.MustCompile(`^errcheck$`)},
+			},
+		},
+		{
+			IssueMatcher: config.IssueMatcher{
+				Category: &config.Regex{Regexp: regexp.MustCompile(`^comments$`)},
+			},
+			Severity: &config.Severity{Severity: api.SeverityInfo},
+		},
+		{
+			IssueMatcher: config.IssueMatcher{
+				Message: &config.Regex{Regexp: regexp.MustCompile(`never`)},
+			},
+		},
+	})
+
+	assert.True(t, f.IgnoreIssue(&issue.LinterIssue{
+		Issue:  &api.Issue{},
+		Linter: "errcheck",
+		Path:   issue.Path{Abs: "/x/foo_test.go"},
+	}))
+	assert.False(t, f.IgnoreIssue(&issue.LinterIssue{
+		Issue:  &api.Issue{},
+		Linter: "errcheck",
+		Path:   issue.Path{Abs: "/x/foo.go"},
+	}))
+	assert.True(t, f.IgnoreIssue(&issue.LinterIssue{
+		Issue: &api.Issue{Category: "comments", Severity: api.SeverityInfo},
+	}))
+	assert.False(t, f.IgnoreIssue(&issue.LinterIssue{
+		Issue: &api.Issue{Category: "comments", Severity: api.SeverityWarning},
+	}))
+
+	r := &testReporter{}
+	rules.ReportUnusedRules(r)
+	assert.Len(t, r.issues, 1)
+	assert.Equal(t, `exclude rule never matched: message="never"`, r.issues[0].Message)
+}
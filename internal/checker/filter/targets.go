@@ -0,0 +1,41 @@
+package filter
+
+import (
+	"path/filepath"
+
+	"github.com/liut0/gomultilinter/internal/checker/issue"
+)
+
+// TargetFilesFilter filters out issues of files which are not targeted
+// if only some files of their package are targeted
+type TargetFilesFilter struct {
+	dirs  map[string]bool
+	files map[string]bool
+}
+
+// NewTargetFilesFilter constructs a new TargetFilesFilter
+func NewTargetFilesFilter() *TargetFilesFilter {
+	return &TargetFilesFilter{
+		dirs:  map[string]bool{},
+		files: map[string]bool{},
+	}
+}
+
+// AddFiles adds targeted files (absolute paths), all other
+// files of their directories are filtered out
+func (f *TargetFilesFilter) AddFiles(files ...string) {
+	for _, file := range files {
+		f.dirs[filepath.Dir(file)] = true
+		f.files[file] = true
+	}
+}
+
+// IgnoreFile returns wether the file is not targeted
+func (f *TargetFilesFilter) IgnoreFile(path string) bool {
+	return f.dirs[filepath.Dir(path)] && !f.files[path]
+}
+
+// IgnoreIssue returns wether the issue is located in a file which is not targeted
+func (f *TargetFilesFilter) IgnoreIssue(issue *issue.LinterIssue) bool {
+	return issue.Position.Filename != "" && f.IgnoreFile(issue.Path.Abs)
+}
@@ -0,0 +1,27 @@
+package filter
+
+import (
+	"go/token"
+	"testing"
+
+	"github.com/liut0/gomultilinter/api"
+	"github.com/liut0/gomultilinter/internal/checker/issue"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetFilesFilter(t *testing.T) {
+	t.Parallel()
+
+	f := NewTargetFilesFilter()
+	f.AddFiles("/src/a/a1.go", "/src/a/a3.go")
+
+	newIssue := func(filename string) *issue.LinterIssue {
+		return issue.ToLinterIssue(&api.Issue{Position: token.Position{Filename: filename, Line: 1}}, "golint")
+	}
+
+	assert.False(t, f.IgnoreIssue(newIssue("/src/a/a1.go")))
+	assert.True(t, f.IgnoreIssue(newIssue("/src/a/a2.go")))
+	assert.False(t, f.IgnoreIssue(newIssue("/src/a/a3.go")))
+	assert.False(t, f.IgnoreIssue(newIssue("/src/b/b1.go")))
+	assert.False(t, f.IgnoreIssue(newIssue("")))
+}
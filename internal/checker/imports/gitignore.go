@@ -0,0 +1,170 @@
+package imports
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/liut0/gomultilinter/internal/files"
+	"github.com/liut0/gomultilinter/internal/glob"
+)
+
+const (
+	gitIgnoreFileName = ".gitignore"
+	gitDirName        = ".git"
+)
+
+// gitIgnore holds the patterns of a .gitignore file
+// which apply relative to its directory
+type gitIgnore struct {
+	dir      string
+	patterns []*gitIgnorePattern
+}
+
+type gitIgnorePattern struct {
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// gitIgnores are the .gitignore files of a directory
+// and its parents ordered from the outermost to the innermost
+type gitIgnores []*gitIgnore
+
+// readGitIgnore reads the .gitignore file of the directory
+// returns nil if the directory does not contain a .gitignore file
+func readGitIgnore(dir string) (*gitIgnore, error) {
+	f, err := os.Open(filepath.Join(dir, gitIgnoreFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	g := &gitIgnore{dir: dir}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if p := parseGitIgnorePattern(scanner.Text()); p != nil {
+			g.patterns = append(g.patterns, p)
+		}
+	}
+
+	return g, scanner.Err()
+}
+
+func parseGitIgnorePattern(line string) *gitIgnorePattern {
+	line = strings.TrimRight(line, " ")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
+
+	p := &gitIgnorePattern{}
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	}
+
+	line = strings.TrimPrefix(line, `\`)
+
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	if strings.Contains(line, "/") {
+		p.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+
+	if line == "" {
+		return nil
+	}
+
+	p.pattern = line
+	return p
+}
+
+// parentGitIgnores reads the .gitignore files of the parent directories
+// of dir up to the root of the git repository
+func parentGitIgnores(dir string) (gitIgnores, error) {
+	var ignores gitIgnores
+	for {
+		// .git is a file in worktrees and submodules
+		if git := filepath.Join(dir, gitDirName); files.DirExists(git) || files.FileExists(git) {
+			return ignores, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ignores, nil
+		}
+		dir = parent
+
+		g, err := readGitIgnore(dir)
+		if err != nil {
+			return nil, err
+		}
+		if g != nil {
+			ignores = append(gitIgnores{g}, ignores...)
+		}
+	}
+}
+
+// ignored returns wether the path is ignored and the pattern which decided it
+// the last matching pattern of the innermost .gitignore file wins
+func (g gitIgnores) ignored(path string, isDir bool) (bool, string) {
+	var (
+		ignored bool
+		reason  string
+	)
+
+	for _, ignore := range g {
+		for _, p := range ignore.patterns {
+			if p.matches(ignore.dir, path, isDir) {
+				ignored = !p.negate
+				reason = filepath.Join(ignore.dir, gitIgnoreFileName) + ": " + p.String()
+			}
+		}
+	}
+
+	return ignored, reason
+}
+
+func (p *gitIgnorePattern) matches(dir, path string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+
+	if !files.ContainsPath(dir, path) {
+		return false
+	}
+
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == "." {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+
+	if p.anchored {
+		return glob.Match(p.pattern, rel)
+	}
+
+	return glob.Match(p.pattern, rel[strings.LastIndex(rel, "/")+1:])
+}
+
+func (p *gitIgnorePattern) String() string {
+	s := p.pattern
+	if p.anchored {
+		s = "/" + s
+	}
+	if p.dirOnly {
+		s += "/"
+	}
+	if p.negate {
+		s = "!" + s
+	}
+	return s
+}
@@ -1,46 +1,88 @@
 package imports
 
 import (
-	"errors"
+	"fmt"
 	"go/build"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 
-	"github.com/liut0/gomultilinter/config"
+	"github.com/liut0/gomultilinter/internal/checker/overlay"
 	"github.com/liut0/gomultilinter/internal/files"
 	"github.com/liut0/gomultilinter/internal/log"
 )
 
 const (
 	recursiveSuffix = "/..."
+	goFileExt       = ".go"
 )
 
-var (
-	ignoredDirs = config.MultiRegex{
-		&config.Regex{Regexp: regexp.MustCompile(`\.git`)},
-		&config.Regex{Regexp: regexp.MustCompile(`vendor`)},
-	}
-)
+// Resolver resolves paths to go packages
+type Resolver struct {
+	// SkipDirs are directories which are skipped when walking directories recursively
+	// matched against consecutive segments of the path relative to the walked directory,
+	// absolute ones are matched against the absolute path of the directory
+	SkipDirs []string
+
+	// GitIgnore if true directories ignored by .gitignore files are skipped
+	GitIgnore bool
+
+	// Context is the build context used to import packages
+	// defaults to build.Default
+	Context *build.Context
+
+	// Overlay contains files which replace/don't exist on the disk
+	Overlay overlay.Overlay
+}
+
+// Targets are resolved paths
+type Targets struct {
+	// Pkgs are the import paths of the targeted packages
+	Pkgs []string
+
+	// Files are the absolute paths of targeted files
+	// whose packages are not targeted completely
+	Files []string
+}
 
 // ResolvePaths resolves paths to go packages
-// paths can either be directories, packages or go files
+// paths can be any mix of directories, packages and go files
 //
 // if a directory path or a package has a '/...' suffix all
 // subpackages/-directories are also included
 //
+// files are resolved to the packages of their directories,
+// if a package is only targeted by files, the files are part of Targets.Files
+//
 // if paths is empty the current directory is used including all
 // subdirectories
-func ResolvePaths(paths ...string) ([]string, error) {
+func (r *Resolver) ResolvePaths(paths ...string) (*Targets, error) {
 	if len(paths) == 0 {
 		paths = []string{"." + recursiveSuffix}
 	}
 
-	imports := make([]string, 0, len(paths))
+	targets := &Targets{}
+	pkgs := map[string]bool{}
+	addPkgs := func(importPaths ...string) {
+		for _, importPath := range importPaths {
+			if !pkgs[importPath] {
+				pkgs[importPath] = true
+				targets.Pkgs = append(targets.Pkgs, importPath)
+			}
+		}
+	}
+
+	// completePkgs are the packages which are targeted completely
+	completePkgs := map[string]bool{}
+	var filePkgs []string
+	var targetFiles []string
 
-	var file, dir, pkg int
 	for _, path := range paths {
+		if strings.HasPrefix(path, "-") {
+			log.WithFields("path", path).Debug("flag after targets")
+			return nil, fmt.Errorf("invalid target %s, ensure flags are before targets/paths", path)
+		}
+
 		rec := strings.HasSuffix(path, recursiveSuffix)
 		cPath := path
 		if rec {
@@ -49,65 +91,186 @@ func ResolvePaths(paths ...string) ([]string, error) {
 
 		switch {
 		case files.DirExists(cPath):
-			dir = 1
-			pkgs, err := resolveDir(cPath, rec)
+			dirPkgs, err := r.resolveDir(cPath, rec)
 			if err != nil {
 				return nil, err
 			}
-			imports = append(imports, pkgs...)
-		case files.FileExists(path):
-			file = 1
-			imports = append(imports, path)
-		default:
-			pkg = 1
-			if rec {
-				pkgs, err := resolveRecursivePkg(cPath)
-				if err != nil {
-					return nil, err
-				}
+			addPkgs(dirPkgs...)
+			for _, dirPkg := range dirPkgs {
+				completePkgs[dirPkg] = true
+			}
+		case r.Overlay.Exists(path):
+			if filepath.Ext(path) != goFileExt {
+				log.WithFields("path", path).Debug("skipping non go file")
+				continue
+			}
 
-				imports = append(imports, pkgs...)
-			} else {
-				imports = append(imports, path)
+			dirPkgs, err := r.resolveDir(filepath.Dir(path), false)
+			if err != nil {
+				return nil, err
+			}
+			if len(dirPkgs) == 0 {
+				log.WithFields("path", path).Debug("file is not part of a package in this build context")
+				continue
+			}
+			addPkgs(dirPkgs...)
+			filePkgs = append(filePkgs, dirPkgs[0])
+			targetFiles = append(targetFiles, files.AbsPath(path))
+		case rec:
+			recPkgs, err := r.resolveRecursivePkg(cPath)
+			if err != nil {
+				return nil, err
+			}
+			addPkgs(recPkgs...)
+			for _, recPkg := range recPkgs {
+				completePkgs[recPkg] = true
 			}
+		default:
+			addPkgs(path)
+			completePkgs[path] = true
 		}
 	}
 
-	if file+dir+pkg != 1 {
-		log.WithFields("file", file, "dir", dir, "pkg", pkg).Debug("multiple target types")
-		return nil, errors.New("multiple target types, ensure flags are before targets/paths")
+	for i, file := range targetFiles {
+		if !completePkgs[filePkgs[i]] {
+			targets.Files = append(targets.Files, file)
+		}
 	}
 
-	return imports, nil
+	return targets, nil
 }
 
-func getRecursiveSubDirs(dir string) []string {
+// SubDirs returns the directory and its sub directories
+// which are not skipped by SkipDirs or .gitignore files
+func (r *Resolver) SubDirs(dir string) ([]string, error) {
+	return r.getRecursiveSubDirs(dir)
+}
+
+func (r *Resolver) getRecursiveSubDirs(dir string) ([]string, error) {
+	var ignores gitIgnores
+	if r.GitIgnore {
+		var err error
+		if ignores, err = parentGitIgnores(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	// .gitignore files of the walked directories, the depth of
+	// the directory is used to drop the ones of sibling directories
+	type dirGitIgnore struct {
+		*gitIgnore
+		depth int
+	}
+	var dirIgnores []dirGitIgnore
+
 	var paths []string
-	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if info.IsDir() && !ignoredDirs.MatchesAny(path) {
-			paths = append(paths, path)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			log.WithFields("err", err, "path", path).Debug("skipping unreadable path")
+			return nil
 		}
+
+		if !info.IsDir() {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(dir, path)
+		depth := 0
+		if rel != "." {
+			depth = len(strings.Split(rel, string(filepath.Separator)))
+		}
+
+		if skipDir, ok := r.matchSkipDirs(path, rel); ok {
+			log.WithFields("dir", path, "skip_dir", skipDir).Debug("skipping directory")
+			return filepath.SkipDir
+		}
+
+		if r.GitIgnore {
+			for len(dirIgnores) > 0 && dirIgnores[len(dirIgnores)-1].depth >= depth {
+				dirIgnores = dirIgnores[:len(dirIgnores)-1]
+			}
+
+			all := append(gitIgnores{}, ignores...)
+			for _, dirIgnore := range dirIgnores {
+				all = append(all, dirIgnore.gitIgnore)
+			}
+
+			// the walked directory itself is targeted explicitly
+			if ignored, reason := all.ignored(path, true); ignored && rel != "." {
+				log.WithFields("dir", path, "gitignore", reason).Debug("skipping directory")
+				return filepath.SkipDir
+			}
+
+			g, err := readGitIgnore(path)
+			if err != nil {
+				return err
+			}
+			if g != nil {
+				dirIgnores = append(dirIgnores, dirGitIgnore{gitIgnore: g, depth: depth})
+			}
+		}
+
+		paths = append(paths, path)
 		return nil
 	})
 
-	return paths
+	return paths, err
 }
 
-func expandRecursivePath(path string, recursive bool) []string {
+// matchSkipDirs returns the skip dir which matches consecutive
+// segments of the relative path or the absolute path
+func (r *Resolver) matchSkipDirs(path, rel string) (string, bool) {
+	if rel == "." {
+		return "", false
+	}
+
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	for _, skipDir := range r.SkipDirs {
+		if filepath.IsAbs(skipDir) {
+			if filepath.Clean(skipDir) == files.AbsPath(path) {
+				return skipDir, true
+			}
+			continue
+		}
+
+		skipSegments := strings.Split(strings.Trim(filepath.ToSlash(skipDir), "/"), "/")
+		for i := 0; i+len(skipSegments) <= len(segments); i++ {
+			if equalSegments(segments[i:i+len(skipSegments)], skipSegments) {
+				return skipDir, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func equalSegments(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Resolver) expandRecursivePath(path string, recursive bool) ([]string, error) {
 	if !recursive {
-		return []string{path}
+		return []string{path}, nil
 	}
 
-	return getRecursiveSubDirs(path)
+	return r.getRecursiveSubDirs(path)
 }
 
-func resolveDir(path string, recursive bool) ([]string, error) {
-	paths := expandRecursivePath(files.AbsPath(path), recursive)
+func (r *Resolver) resolveDir(path string, recursive bool) ([]string, error) {
+	paths, err := r.expandRecursivePath(files.AbsPath(path), recursive)
+	if err != nil {
+		return nil, err
+	}
 
 	pkgs := make([]string, 0, len(paths))
 	for _, path := range paths {
 
-		pkg, err := build.ImportDir(path, 0)
+		pkg, err := r.context().ImportDir(path, 0)
 		if err != nil {
 			if _, isNoGo := err.(*build.NoGoError); isNoGo {
 				continue
@@ -123,17 +286,24 @@ func resolveDir(path string, recursive bool) ([]string, error) {
 	return pkgs, nil
 }
 
-func resolveRecursivePkg(pkgImportPath string) ([]string, error) {
-	pkg, err := build.Import(pkgImportPath, ".", build.FindOnly)
+func (r *Resolver) resolveRecursivePkg(pkgImportPath string) ([]string, error) {
+	pkg, err := r.context().Import(pkgImportPath, ".", build.FindOnly)
 	if err != nil {
 		log.WithFields("err", err, "pkg", pkgImportPath).Debug("error importing pkgImportPath")
 		return nil, err
 	}
 
-	pkgs, err := resolveDir(pkg.Dir, true)
+	pkgs, err := r.resolveDir(pkg.Dir, true)
 	if err != nil {
 		return nil, err
 	}
 
 	return pkgs, nil
 }
+
+func (r *Resolver) context() *build.Context {
+	if r.Context == nil {
+		return &build.Default
+	}
+	return r.Context
+}
@@ -0,0 +1,136 @@
+package imports
+
+import (
+	"go/build"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRecursiveSubDirs(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "gomultilinter")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, d := range []string{
+		".git",
+		"myvendorutils",
+		"vendor/foo",
+		"build/out",
+		"a/third_party/gen",
+		"a/third_party/keep",
+		"a/tmp",
+		"b/tmp",
+		"b/keep",
+		"c/skip",
+		"c/d/skip",
+	} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, d), os.ModePerm))
+	}
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, ".gitignore"), []byte("# comment\n/build/\ntmp\n"), os.ModePerm))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "b", ".gitignore"), []byte("!tmp\nkeep/\n"), os.ModePerm))
+
+	r := &Resolver{
+		SkipDirs:  []string{".git", "vendor", "third_party/gen", filepath.Join(dir, "c", "skip")},
+		GitIgnore: true,
+	}
+
+	paths, err := r.getRecursiveSubDirs(filepath.Join(dir, "a"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "a"),
+		filepath.Join(dir, "a", "third_party"),
+		filepath.Join(dir, "a", "third_party", "keep"),
+	}, paths)
+
+	paths, err = r.getRecursiveSubDirs(dir)
+	assert.NoError(t, err)
+
+	rel := make([]string, 0, len(paths))
+	for _, p := range paths {
+		r, _ := filepath.Rel(dir, p)
+		rel = append(rel, filepath.ToSlash(r))
+	}
+	sort.Strings(rel)
+
+	assert.Equal(t, []string{
+		".",
+		"a",
+		"a/third_party",
+		"a/third_party/keep",
+		"b",
+		"b/tmp",
+		"c",
+		"c/d",
+		"c/d/skip",
+		"myvendorutils",
+	}, rel)
+
+	// explicit targets are not filtered by the .gitignore files of their parents
+	paths, err = r.getRecursiveSubDirs(filepath.Join(dir, "a", "tmp"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "a", "tmp")}, paths)
+}
+
+func TestGetRecursiveSubDirsWorktree(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "gomultilinter")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// .git is a file in worktrees and submodules, the .gitignore
+	// files above the repository do not apply to it
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "worktree", "pkg"), os.ModePerm))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, ".gitignore"), []byte("pkg\n"), os.ModePerm))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "worktree", ".git"), []byte("gitdir: ../.git/worktrees/worktree\n"), os.ModePerm))
+
+	r := &Resolver{GitIgnore: true}
+	paths, err := r.getRecursiveSubDirs(filepath.Join(dir, "worktree"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "worktree"),
+		filepath.Join(dir, "worktree", "pkg"),
+	}, paths)
+}
+
+func TestResolvePathsMixedTargets(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "gomultilinter")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ctx := build.Default
+	ctx.GOPATH = dir
+	src := filepath.Join(dir, "src", "x")
+
+	for _, f := range []string{"a/a1.go", "a/a2.go", "b/b1.go", "b/b2.go", "c/c.go", "c/README.md"} {
+		path := filepath.Join(src, f)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		assert.NoError(t, ioutil.WriteFile(path, []byte("package "+filepath.Base(filepath.Dir(path))+"\n"), os.ModePerm))
+	}
+
+	r := &Resolver{Context: &ctx}
+
+	targets, err := r.ResolvePaths(
+		filepath.Join(src, "a", "a1.go"),
+		filepath.Join(src, "b", "b1.go"),
+		filepath.Join(src, "a", "a2.go"),
+		filepath.Join(src, "b"),
+		filepath.Join(src, "c", "README.md"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"x/a", "x/b"}, targets.Pkgs)
+		assert.Equal(t, []string{filepath.Join(src, "a", "a1.go"), filepath.Join(src, "a", "a2.go")}, targets.Files)
+	}
+
+	_, err = r.ResolvePaths(filepath.Join(src, "a"), "-v")
+	assert.Error(t, err)
+}
@@ -3,6 +3,8 @@
 package issue
 
 import (
+	"sort"
+
 	"github.com/liut0/gomultilinter/api"
 	"github.com/liut0/gomultilinter/internal/files"
 )
@@ -13,6 +15,15 @@ type LinterIssue struct {
 	*api.Issue
 	Linter string
 	Path   Path
+
+	// Package is the import path of the linted package
+	// empty if the issue is not related to a package
+	Package string
+
+	// BuildContexts are the names of the build contexts
+	// in which the issue occurred, empty if only a single
+	// build context is linted
+	BuildContexts []string
 }
 
 // Path wraps rel/abs paths
@@ -46,3 +57,80 @@ func ToLinterIssue(issue *api.Issue, linter string) *LinterIssue {
 		},
 	}
 }
+
+// Sort sorts the issues by their path, line, column and linter
+// (followed by severity, category and message to get a deterministic order)
+func Sort(issues []*LinterIssue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		switch {
+		case a.Path.Abs != b.Path.Abs:
+			return a.Path.Abs < b.Path.Abs
+		case a.Line() != b.Line():
+			return a.Line() < b.Line()
+		case a.Col() != b.Col():
+			return a.Col() < b.Col()
+		case a.Linter != b.Linter:
+			return a.Linter < b.Linter
+		case a.Severity != b.Severity:
+			return a.Severity > b.Severity
+		case a.Category != b.Category:
+			return a.Category < b.Category
+		default:
+			return a.Message < b.Message
+		}
+	})
+}
+
+// duplicateKey identifies an issue regardless of the linter which reported it
+type duplicateKey struct {
+	path     string
+	line     int
+	column   int
+	severity api.Severity
+	category string
+	message  string
+}
+
+// Deduplicate drops issues which were already reported at the same
+// position with the same severity, category and message (e.g. by another linter)
+// the build contexts of dropped issues are added to the kept ones
+func Deduplicate(issues []*LinterIssue) []*LinterIssue {
+	deduplicated := make([]*LinterIssue, 0, len(issues))
+	index := map[duplicateKey]*LinterIssue{}
+
+	for _, iss := range issues {
+		key := duplicateKey{
+			path:     iss.Path.Abs,
+			line:     iss.Line(),
+			column:   iss.Col(),
+			severity: iss.Severity,
+			category: iss.Category,
+			message:  iss.Message,
+		}
+
+		kept, ok := index[key]
+		if !ok {
+			index[key] = iss
+			deduplicated = append(deduplicated, iss)
+			continue
+		}
+
+		for _, ctx := range iss.BuildContexts {
+			if !containsString(kept.BuildContexts, ctx) {
+				kept.BuildContexts = append(kept.BuildContexts, ctx)
+			}
+		}
+	}
+
+	return deduplicated
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package issue
+
+import (
+	"go/token"
+	"testing"
+
+	"github.com/liut0/gomultilinter/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortDeduplicate(t *testing.T) {
+	t.Parallel()
+
+	newIssue := func(linter, filename string, line, col int, message string, buildContexts ...string) *LinterIssue {
+		iss := ToLinterIssue(&api.Issue{
+			Position: token.Position{Filename: filename, Line: line, Column: col},
+			Severity: api.SeverityWarning,
+			Category: "cat",
+			Message:  message,
+		}, linter)
+		iss.BuildContexts = buildContexts
+		return iss
+	}
+
+	issues := []*LinterIssue{
+		newIssue("vet", "/src/b.go", 1, 1, "foo"),
+		newIssue("golint", "/src/a.go", 10, 1, "foo", "linux/amd64"),
+		newIssue("errcheck", "/src/a.go", 2, 5, "bar"),
+		newIssue("errcheck", "/src/a.go", 2, 3, "foo"),
+		newIssue("vet", "/src/a.go", 10, 1, "foo", "windows/amd64"),
+		newIssue("golint", "/src/a.go", 2, 5, "bar"),
+	}
+
+	Sort(issues)
+	issues = Deduplicate(issues)
+
+	var got []string
+	for _, iss := range issues {
+		got = append(got, iss.Linter+":"+iss.Path.Abs+":"+iss.Message)
+	}
+	assert.Equal(t, []string{
+		"errcheck:/src/a.go:foo",
+		"errcheck:/src/a.go:bar",
+		"golint:/src/a.go:foo",
+		"vet:/src/b.go:foo",
+	}, got)
+	assert.Equal(t, []string{"linux/amd64", "windows/amd64"}, issues[2].BuildContexts)
+}
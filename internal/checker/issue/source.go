@@ -0,0 +1,110 @@
+package issue
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/liut0/gomultilinter/internal/checker/overlay"
+	"github.com/liut0/gomultilinter/internal/files"
+	"github.com/liut0/gomultilinter/internal/log"
+)
+
+const (
+	maxSourceLineLength = 1024 * 1024
+
+	// maxCachedSources is the number of files whose lines are cached by Sources
+	maxCachedSources = 8
+)
+
+// Sources reads the lines of the source files of issues
+// the files of the overlay replace the ones on the disk (like when linting them)
+// the lines of the recently read files are cached
+type Sources struct {
+	lock    sync.Mutex
+	overlay overlay.Overlay
+	files   map[string][]string
+	// recent are the cached paths in the order they were read
+	recent []string
+}
+
+// NewSources constructs new Sources reading the files through the overlay
+func NewSources(o overlay.Overlay) *Sources {
+	return &Sources{
+		overlay: o,
+		files:   map[string][]string{},
+	}
+}
+
+// SetOverlay sets the overlay whose files replace the ones on the disk
+func (s *Sources) SetOverlay(o overlay.Overlay) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.overlay = o
+	s.files = map[string][]string{}
+	s.recent = nil
+}
+
+// Content returns the content of the file
+// nil Sources read the file from the disk
+func (s *Sources) Content(path string) ([]byte, error) {
+	if s != nil {
+		if content, ok := s.overlay[files.AbsPath(path)]; ok {
+			if content == nil {
+				return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+			}
+			return content, nil
+		}
+	}
+	return ioutil.ReadFile(path)
+}
+
+// Line returns the line (starting at 1) of the file
+func (s *Sources) Line(path string, line int) (string, bool) {
+	lines := s.lines(path)
+	if line <= 0 || line > len(lines) {
+		return "", false
+	}
+	return lines[line-1], true
+}
+
+func (s *Sources) lines(path string) []string {
+	if s == nil {
+		return s.readLines(path)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if lines, ok := s.files[path]; ok {
+		return lines
+	}
+
+	lines := s.readLines(path)
+	s.files[path] = lines
+	s.recent = append(s.recent, path)
+	if len(s.recent) > maxCachedSources {
+		delete(s.files, s.recent[0])
+		s.recent = s.recent[1:]
+	}
+	return lines
+}
+
+func (s *Sources) readLines(path string) []string {
+	content, err := s.Content(path)
+	if err != nil {
+		log.WithFields("err", err, "path", path).Debug("could not read source file")
+		return nil
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(nil, maxSourceLineLength)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
@@ -0,0 +1,72 @@
+package issue
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liut0/gomultilinter/internal/checker/overlay"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSources(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "gomultilinter")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	disk := filepath.Join(dir, "disk.go")
+	overlaid := filepath.Join(dir, "overlaid.go")
+	deleted := filepath.Join(dir, "deleted.go")
+	for _, path := range []string{disk, overlaid, deleted} {
+		assert.NoError(t, ioutil.WriteFile(path, []byte("package disk\n"), os.ModePerm))
+	}
+
+	s := NewSources(overlay.Overlay{
+		overlaid: []byte("package overlaid\n\nvar a = 1\n"),
+		deleted:  nil,
+	})
+
+	line, ok := s.Line(disk, 1)
+	assert.True(t, ok)
+	assert.Equal(t, "package disk", line)
+
+	line, ok = s.Line(overlaid, 3)
+	assert.True(t, ok)
+	assert.Equal(t, "var a = 1", line)
+
+	_, ok = s.Line(overlaid, 4)
+	assert.False(t, ok)
+
+	_, ok = s.Line(deleted, 1)
+	assert.False(t, ok)
+
+	s.SetOverlay(nil)
+	line, ok = s.Line(overlaid, 1)
+	assert.True(t, ok)
+	assert.Equal(t, "package disk", line)
+
+	var nilSources *Sources
+	line, ok = nilSources.Line(disk, 1)
+	assert.True(t, ok)
+	assert.Equal(t, "package disk", line)
+}
+
+func TestSourcesCacheBounded(t *testing.T) {
+	t.Parallel()
+
+	s := NewSources(overlay.Overlay{})
+	for i := 0; i < 2*maxCachedSources; i++ {
+		path := fmt.Sprintf("/src/file%d.go", i)
+		s.overlay[path] = []byte("package file\n")
+		s.Line(path, 1)
+	}
+
+	assert.Len(t, s.files, maxCachedSources)
+	assert.Len(t, s.recent, maxCachedSources)
+	assert.Contains(t, s.files, fmt.Sprintf("/src/file%d.go", 2*maxCachedSources-1))
+	assert.NotContains(t, s.files, "/src/file0.go")
+}
@@ -0,0 +1,66 @@
+package checker
+
+import (
+	"github.com/liut0/gomultilinter/config"
+	"github.com/liut0/gomultilinter/internal/checker/issue"
+)
+
+// issueLimits limits the number of written issues
+// a limit <= 0 means unlimited
+type issueLimits struct {
+	maxIssues          int
+	maxIssuesPerLinter int
+	maxIssuesPerFile   int
+	maxSameIssues      int
+
+	// counts of the allowed issues
+	shown     int
+	perLinter map[string]int
+	perFile   map[string]int
+	same      map[sameIssueKey]int
+}
+
+// sameIssueKey identifies issues which are the same except their position
+type sameIssueKey struct {
+	linter   string
+	category string
+	message  string
+}
+
+func newIssueLimits(conf *config.Config) *issueLimits {
+	return &issueLimits{
+		maxIssues:          conf.MaxIssues,
+		maxIssuesPerLinter: conf.MaxIssuesPerLinter,
+		maxIssuesPerFile:   conf.MaxIssuesPerFile,
+		maxSameIssues:      conf.MaxSameIssues,
+	}
+}
+
+// allow returns wether the issue is within the limits
+// allowed issues are counted
+func (l *issueLimits) allow(iss *issue.LinterIssue) bool {
+	if l.perLinter == nil {
+		l.perLinter = map[string]int{}
+		l.perFile = map[string]int{}
+		l.same = map[sameIssueKey]int{}
+	}
+
+	key := sameIssueKey{linter: iss.Linter, category: iss.Category, message: iss.Message}
+
+	if exceeds(l.shown, l.maxIssues) ||
+		exceeds(l.perLinter[iss.Linter], l.maxIssuesPerLinter) ||
+		exceeds(l.perFile[iss.Path.Abs], l.maxIssuesPerFile) ||
+		exceeds(l.same[key], l.maxSameIssues) {
+		return false
+	}
+
+	l.shown++
+	l.perLinter[iss.Linter]++
+	l.perFile[iss.Path.Abs]++
+	l.same[key]++
+	return true
+}
+
+func exceeds(count, limit int) bool {
+	return limit > 0 && count >= limit
+}
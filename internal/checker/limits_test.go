@@ -0,0 +1,68 @@
+package checker
+
+import (
+	"go/token"
+	"testing"
+
+	"github.com/liut0/gomultilinter/api"
+	"github.com/liut0/gomultilinter/internal/checker/issue"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckerWriteIssueLimits(t *testing.T) {
+	t.Parallel()
+
+	newIssue := func(linter, filename, message string) *issue.LinterIssue {
+		return issue.ToLinterIssue(&api.Issue{
+			Position: token.Position{Filename: filename, Line: 1},
+			Category: "cat",
+			Message:  message,
+		}, linter)
+	}
+
+	issues := []*issue.LinterIssue{
+		newIssue("golint", "/src/a.go", "a"),
+		newIssue("golint", "/src/a.go", "a"),
+		newIssue("golint", "/src/b.go", "b"),
+		newIssue("golint", "/src/c.go", "c"),
+		newIssue("vet", "/src/a.go", "d"),
+		newIssue("vet", "/src/b.go", "e"),
+		newIssue("errcheck", "/src/c.go", "f"),
+	}
+
+	write := func(limits *issueLimits) ([]*issue.LinterIssue, int) {
+		w := &recordingWriter{}
+		c := &Checker{limits: limits, issueWriter: w}
+		for _, iss := range issues {
+			c.writeIssue(iss)
+		}
+		return w.issues, c.hiddenIssues
+	}
+
+	shown, hidden := write(&issueLimits{})
+	assert.Len(t, shown, 7)
+	assert.Equal(t, 0, hidden)
+
+	shown, hidden = write(&issueLimits{maxSameIssues: 1, maxIssuesPerLinter: 2})
+	assert.Equal(t, []*issue.LinterIssue{issues[0], issues[2], issues[4], issues[5], issues[6]}, shown)
+	assert.Equal(t, 2, hidden)
+
+	shown, hidden = write(&issueLimits{maxIssuesPerFile: 1, maxIssues: 3})
+	assert.Equal(t, []*issue.LinterIssue{issues[0], issues[2], issues[3]}, shown)
+	assert.Equal(t, 4, hidden)
+}
+
+// recordingWriter records the written issues
+// and returns closeErr on Close
+type recordingWriter struct {
+	issues   []*issue.LinterIssue
+	closeErr error
+}
+
+func (w *recordingWriter) Write(iss *issue.LinterIssue) {
+	w.issues = append(w.issues, iss)
+}
+
+func (w *recordingWriter) Close() error {
+	return w.closeErr
+}
@@ -15,27 +15,34 @@ const (
 	linterErrorCategory = "linter-error"
 )
 
-func (c *Checker) lintPkg(pkg *api.Package) {
-	for linterName, l := range c.pkgLinter {
-		r := c.issueReporter.entry(linterName)
-		c.runLinter(r, func() error {
+func (c *Checker) lintPkg(s *scope, pkg *api.Package) {
+	for linterName, l := range s.pkgLinter {
+		r := s.issueReporter.entry(linterName, c.buildContext, pkg.PkgInfo.Pkg.Path())
+		c.runLinter(r, pkg, func() error {
 			return l.LintPackage(c.ctx, pkg, r)
 		})
 	}
 }
 
-func (c *Checker) lintFile(file *api.File) {
-	for linterName, l := range c.fileLinter {
-		r := c.issueReporter.entry(linterName)
-		c.runLinter(r, func() error {
+func (c *Checker) lintFile(s *scope, file *api.File) {
+	for linterName, l := range s.fileLinter {
+		r := s.issueReporter.entry(linterName, c.buildContext, file.PkgInfo.Pkg.Path())
+		c.runLinter(r, file.Package, func() error {
 			return l.LintFile(c.ctx, file, r)
 		})
 	}
 }
 
-func (c *Checker) runLinter(reporter api.IssueReporter, f func() error) {
+func (c *Checker) runLinter(reporter *IssueReporterEntry, pkg *api.Package, f func() error) {
+	c.metrics.measure(reporter.linter, pkg.PkgInfo.Pkg.Path(), func() {
+		c.runLinterRecovered(reporter, f)
+	})
+}
+
+func (c *Checker) runLinterRecovered(reporter *IssueReporterEntry, f func() error) {
 	defer func() {
 		if err := recover(); err != nil {
+			c.linterPanics[reporter.linter]++
 			reporter.Report(&api.Issue{
 				Message:  fmt.Sprintf(linterPanciMsg, err),
 				Position: token.Position{},
@@ -47,6 +54,7 @@ func (c *Checker) runLinter(reporter api.IssueReporter, f func() error) {
 	}()
 
 	if err := f(); err != nil {
+		c.linterErrors[reporter.linter]++
 		reporter.Report(&api.Issue{
 			Message:  fmt.Sprintf(linterErrorMsg, err),
 			Position: token.Position{},
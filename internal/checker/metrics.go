@@ -0,0 +1,166 @@
+package checker
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Metrics are the performance metrics of the linters
+type Metrics struct {
+	// Linters are the metrics per linter, the slowest first
+	Linters []*LinterMetrics `json:"linters"`
+
+	// Packages are the metrics per package summed up over all linters, the slowest first
+	Packages []*PackageMetrics `json:"packages"`
+}
+
+// LinterMetrics are the performance metrics of a linter
+type LinterMetrics struct {
+	Linter   string        `json:"linter"`
+	Duration time.Duration `json:"duration_ns"`
+
+	// SlowestPackages are the slowest packages of the linter
+	SlowestPackages []*PackageMetrics `json:"slowest_packages"`
+}
+
+// PackageMetrics are the performance metrics of linting a package
+// the allocations are only measured for all linters of the package
+type PackageMetrics struct {
+	Package  string        `json:"package"`
+	Duration time.Duration `json:"duration_ns"`
+	Mallocs  uint64        `json:"mallocs,omitempty"`
+}
+
+// metricsCollector collects the metrics of the linter invocations
+// the allocations are measured process wide (runtime.ReadMemStats stops the world,
+// so they are sampled once per package instead of per linter invocation)
+// and only accurate as long as the packages are linted sequentially
+type metricsCollector struct {
+	lock     sync.Mutex
+	measures map[string]map[string]*PackageMetrics
+	mallocs  map[string]uint64
+}
+
+func newMetricsCollector() *metricsCollector {
+	return &metricsCollector{
+		measures: map[string]map[string]*PackageMetrics{},
+		mallocs:  map[string]uint64{},
+	}
+}
+
+// measure runs f and adds its wall time to the metrics
+// of the linter and package, f is just run if m is nil
+func (m *metricsCollector) measure(linter, pkg string, f func()) {
+	if m == nil {
+		f()
+		return
+	}
+
+	start := time.Now()
+	defer func() {
+		m.add(linter, pkg, time.Since(start))
+	}()
+
+	f()
+}
+
+// measurePackage runs f which lints the package and adds its allocations
+// to the metrics of the package, f is just run if m is nil
+func (m *metricsCollector) measurePackage(pkg string, f func()) {
+	if m == nil {
+		f()
+		return
+	}
+
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	mallocs := memStats.Mallocs
+
+	defer func() {
+		runtime.ReadMemStats(&memStats)
+		m.addMallocs(pkg, memStats.Mallocs-mallocs)
+	}()
+
+	f()
+}
+
+func (m *metricsCollector) addMallocs(pkg string, mallocs uint64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.mallocs[pkg] += mallocs
+}
+
+func (m *metricsCollector) add(linter, pkg string, elapsed time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	pkgs, ok := m.measures[linter]
+	if !ok {
+		pkgs = map[string]*PackageMetrics{}
+		m.measures[linter] = pkgs
+	}
+
+	pkgMetrics, ok := pkgs[pkg]
+	if !ok {
+		pkgMetrics = &PackageMetrics{Package: pkg}
+		pkgs[pkg] = pkgMetrics
+	}
+
+	pkgMetrics.Duration += elapsed
+}
+
+// metrics returns the collected metrics
+// with the slowest n packages per linter
+func (m *metricsCollector) metrics(n int) *Metrics {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	metrics := &Metrics{}
+	pkgTotals := map[string]*PackageMetrics{}
+
+	for linter, pkgs := range m.measures {
+		linterMetrics := &LinterMetrics{Linter: linter}
+		for pkg, pkgMetrics := range pkgs {
+			linterMetrics.Duration += pkgMetrics.Duration
+			linterMetrics.SlowestPackages = append(linterMetrics.SlowestPackages, pkgMetrics)
+
+			total, ok := pkgTotals[pkg]
+			if !ok {
+				total = &PackageMetrics{Package: pkg, Mallocs: m.mallocs[pkg]}
+				pkgTotals[pkg] = total
+				metrics.Packages = append(metrics.Packages, total)
+			}
+			total.Duration += pkgMetrics.Duration
+		}
+
+		sortPackageMetrics(linterMetrics.SlowestPackages)
+		if n > 0 && len(linterMetrics.SlowestPackages) > n {
+			linterMetrics.SlowestPackages = linterMetrics.SlowestPackages[:n]
+		}
+		metrics.Linters = append(metrics.Linters, linterMetrics)
+	}
+
+	sort.Slice(metrics.Linters, func(i, j int) bool {
+		a, b := metrics.Linters[i], metrics.Linters[j]
+		if a.Duration != b.Duration {
+			return a.Duration > b.Duration
+		}
+		return a.Linter < b.Linter
+	})
+	sortPackageMetrics(metrics.Packages)
+
+	return metrics
+}
+
+// sortPackageMetrics sorts the metrics by their duration, the slowest first
+func sortPackageMetrics(pkgs []*PackageMetrics) {
+	sort.Slice(pkgs, func(i, j int) bool {
+		if pkgs[i].Duration != pkgs[j].Duration {
+			return pkgs[i].Duration > pkgs[j].Duration
+		}
+		return pkgs[i].Package < pkgs[j].Package
+	})
+}
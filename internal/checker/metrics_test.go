@@ -0,0 +1,49 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsCollector(t *testing.T) {
+	t.Parallel()
+
+	m := newMetricsCollector()
+	m.add("golint", "a", 3*time.Millisecond)
+	m.add("golint", "a", 2*time.Millisecond)
+	m.add("golint", "b", 4*time.Millisecond)
+	m.add("golint", "c", 1*time.Millisecond)
+	m.add("vet", "a", 20*time.Millisecond)
+	m.addMallocs("a", 250)
+	m.addMallocs("b", 40)
+	m.addMallocs("c", 10)
+
+	metrics := m.metrics(2)
+	if assert.Len(t, metrics.Linters, 2) {
+		assert.Equal(t, "vet", metrics.Linters[0].Linter)
+
+		golint := metrics.Linters[1]
+		assert.Equal(t, 10*time.Millisecond, golint.Duration)
+		assert.Equal(t, []*PackageMetrics{
+			{Package: "a", Duration: 5 * time.Millisecond},
+			{Package: "b", Duration: 4 * time.Millisecond},
+		}, golint.SlowestPackages)
+	}
+
+	assert.Equal(t, []*PackageMetrics{
+		{Package: "a", Duration: 25 * time.Millisecond, Mallocs: 250},
+		{Package: "b", Duration: 4 * time.Millisecond, Mallocs: 40},
+		{Package: "c", Duration: 1 * time.Millisecond, Mallocs: 10},
+	}, metrics.Packages)
+
+	var disabled *metricsCollector
+	called := false
+	disabled.measure("golint", "a", func() { called = true })
+	assert.True(t, called)
+
+	called = false
+	disabled.measurePackage("a", func() { called = true })
+	assert.True(t, called)
+}
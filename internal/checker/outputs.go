@@ -0,0 +1,144 @@
+package checker
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/liut0/gomultilinter/api"
+	"github.com/liut0/gomultilinter/config"
+	"github.com/liut0/gomultilinter/internal/checker/issue"
+	"github.com/liut0/gomultilinter/internal/log"
+)
+
+// newIssueWriter returns the writer of the config's outputs
+// if no outputs are configured the issues are written to the console
+// in the config's output style
+// sources are used by the writers to read the source lines of the issues
+func newIssueWriter(conf *config.Config, sources *issue.Sources) (IssueWriter, error) {
+	if len(conf.Outputs) == 0 {
+		if conf.OutputStyle == config.OutputStyleTemplate ||
+			(conf.OutputStyle == config.OutputStyleAuto && !isTerminal(os.Stdout)) {
+			return newConsoleWriter(conf.OutputFormat, sources)
+		}
+		return newStyleWriter(conf.OutputStyle, conf.OutputFormat, os.Stdout, sources)
+	}
+
+	writers := make(multiWriter, 0, len(conf.Outputs))
+	for _, o := range conf.Outputs {
+		w, err := newOutputWriter(o, conf.OutputFormat, sources)
+		if err != nil {
+			writers.Close()
+			return nil, err
+		}
+		writers = append(writers, w)
+	}
+	return writers, nil
+}
+
+// newOutputWriter opens the destination of the output
+// and returns the writer of the output's style
+func newOutputWriter(o *config.OutputConfig, defaultFormat string, sources *issue.Sources) (IssueWriter, error) {
+	format := o.Format
+	if format == "" {
+		format = defaultFormat
+	}
+
+	var out *os.File
+	switch o.Path {
+	case "", config.OutputPathStdout:
+		out = os.Stdout
+	case config.OutputPathStderr:
+		out = os.Stderr
+	default:
+		var err error
+		if out, err = os.Create(o.Path); err != nil {
+			log.WithFields("err", err, "path", o.Path).Debug("could not create output file")
+			return nil, fmt.Errorf("could not create output file %v", err)
+		}
+	}
+
+	w, err := newStyleWriter(o.Style, format, out, sources)
+	if err != nil {
+		if out != os.Stdout && out != os.Stderr {
+			out.Close()
+		}
+		return nil, err
+	}
+
+	if out != os.Stdout && out != os.Stderr {
+		w = &closingWriter{IssueWriter: w, closer: out}
+	}
+	if o.MinSeverity != nil {
+		w = &severityWriter{IssueWriter: w, minSeverity: o.MinSeverity.Severity}
+	}
+	return w, nil
+}
+
+// newStyleWriter returns the writer of the style which writes to out
+// auto uses the pretty style if out is a terminal
+func newStyleWriter(style config.OutputStyle, format string, out *os.File, sources *issue.Sources) (IssueWriter, error) {
+	switch style {
+	case config.OutputStylePretty:
+		return newPrettyWriter(out, sources), nil
+	case config.OutputStyleJSON:
+		return &jsonWriter{out: out}, nil
+	case config.OutputStyleCheckstyle:
+		return &checkstyleWriter{out: out}, nil
+	case config.OutputStyleSARIF:
+		return &sarifWriter{out: out}, nil
+	case config.OutputStyleTemplate:
+		return newTemplateWriter(format, out, sources)
+	default:
+		if isTerminal(out) {
+			return newPrettyWriter(out, sources), nil
+		}
+		return newTemplateWriter(format, out, sources)
+	}
+}
+
+// multiWriter writes the issues to all writers
+type multiWriter []IssueWriter
+
+func (w multiWriter) Write(issue *issue.LinterIssue) {
+	for _, writer := range w {
+		writer.Write(issue)
+	}
+}
+
+// Close closes all writers and returns the first error
+func (w multiWriter) Close() error {
+	var firstErr error
+	for _, writer := range w {
+		if err := writer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// severityWriter only writes issues with at least the min severity
+type severityWriter struct {
+	IssueWriter
+	minSeverity api.Severity
+}
+
+func (w *severityWriter) Write(issue *issue.LinterIssue) {
+	if issue.Severity >= w.minSeverity {
+		w.IssueWriter.Write(issue)
+	}
+}
+
+// closingWriter closes the file after the writer was closed
+type closingWriter struct {
+	IssueWriter
+	closer io.Closer
+}
+
+func (w *closingWriter) Close() error {
+	err := w.IssueWriter.Close()
+	if closeErr := w.closer.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,85 @@
+package checker
+
+import (
+	"encoding/json"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liut0/gomultilinter/api"
+	"github.com/liut0/gomultilinter/config"
+	"github.com/liut0/gomultilinter/internal/checker/issue"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputs(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "gomultilinter")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	jsonPath := filepath.Join(dir, "issues.json")
+	checkstylePath := filepath.Join(dir, "checkstyle.xml")
+	sarifPath := filepath.Join(dir, "issues.sarif")
+
+	w, err := newIssueWriter(&config.Config{Outputs: []*config.OutputConfig{
+		{Style: config.OutputStyleJSON, Path: jsonPath, MinSeverity: &config.Severity{Severity: api.SeverityWarning}},
+		{Style: config.OutputStyleCheckstyle, Path: checkstylePath},
+		{Style: config.OutputStyleSARIF, Path: sarifPath},
+	}}, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	w.Write(issue.ToLinterIssue(&api.Issue{
+		Position: token.Position{Filename: "/src/foo.go", Line: 3, Column: 2},
+		Severity: api.SeverityInfo,
+		Category: "comments",
+		Message:  "exported function Foo should have comment",
+	}, "golint"))
+	w.Write(issue.ToLinterIssue(&api.Issue{
+		Position: token.Position{Filename: "/src/foo.go", Line: 4, Column: 9},
+		End:      token.Position{Filename: "/src/foo.go", Line: 4, Column: 14},
+		Severity: api.SeverityError,
+		Category: "unchecked",
+		Message:  `error "err" not checked`,
+	}, "errcheck"))
+	assert.NoError(t, w.Close())
+
+	var jsonIssues []map[string]interface{}
+	content, err := ioutil.ReadFile(jsonPath)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(content, &jsonIssues))
+	if assert.Len(t, jsonIssues, 1) {
+		assert.Equal(t, "errcheck", jsonIssues[0]["linter"])
+		assert.Equal(t, "error", jsonIssues[0]["severity"])
+		assert.Equal(t, float64(14), jsonIssues[0]["end_column"])
+	}
+
+	content, err = ioutil.ReadFile(checkstylePath)
+	assert.NoError(t, err)
+	rel := issue.ToLinterIssue(&api.Issue{Position: token.Position{Filename: "/src/foo.go"}}, "").Path.String()
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="5.0">
+  <file name="`+rel+`">
+    <error line="3" column="2" severity="info" message="exported function Foo should have comment" source="golint.comments"></error>
+    <error line="4" column="9" severity="error" message="error &#34;err&#34; not checked" source="errcheck.unchecked"></error>
+  </file>
+</checkstyle>
+`, string(content))
+
+	var sarif sarifLog
+	content, err = ioutil.ReadFile(sarifPath)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(content, &sarif))
+	if assert.Len(t, sarif.Runs, 1) && assert.Len(t, sarif.Runs[0].Results, 2) {
+		result := sarif.Runs[0].Results[1]
+		assert.Equal(t, "errcheck/unchecked", result.RuleID)
+		assert.Equal(t, "error", result.Level)
+		assert.Equal(t, &sarifRegion{StartLine: 4, StartColumn: 9, EndLine: 4, EndColumn: 14}, result.Locations[0].PhysicalLocation.Region)
+		assert.Equal(t, "note", sarif.Runs[0].Results[0].Level)
+	}
+}
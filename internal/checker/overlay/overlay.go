@@ -0,0 +1,155 @@
+// Package overlay replaces the contents of files when loading packages
+// similar to the -overlay flag of go build
+package overlay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/build"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/liut0/gomultilinter/internal/files"
+	"github.com/liut0/gomultilinter/internal/log"
+)
+
+// Overlay maps absolute file paths to their replaced contents
+// a nil content marks the file as deleted
+type Overlay map[string][]byte
+
+// overlayFile is the format of the go build -overlay file
+type overlayFile struct {
+	Replace map[string]string
+}
+
+// ReadFile reads an overlay file in the format of go build -overlay
+// relative paths are relative to the working directory
+func ReadFile(path string) (Overlay, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.WithFields("err", err, "path", path).Debug("could not read overlay file")
+		return nil, fmt.Errorf("could not read overlay file %v", err)
+	}
+
+	var parsed overlayFile
+	if err := json.Unmarshal(content, &parsed); err != nil {
+		log.WithFields("err", err, "path", path).Debug("could not parse overlay file")
+		return nil, fmt.Errorf("could not parse overlay file %s: %v", path, err)
+	}
+
+	o := Overlay{}
+	for filename, replacement := range parsed.Replace {
+		if replacement == "" {
+			o[files.AbsPath(filename)] = nil
+			continue
+		}
+
+		replaced, err := ioutil.ReadFile(replacement)
+		if err != nil {
+			log.WithFields("err", err, "file", filename, "replacement", replacement).Debug("could not read overlay replacement")
+			return nil, fmt.Errorf("could not read replacement of %s: %v", filename, err)
+		}
+		o.Add(filename, replaced)
+	}
+	return o, nil
+}
+
+// Add replaces the content of the file
+func (o Overlay) Add(filename string, content []byte) {
+	if content == nil {
+		content = []byte{}
+	}
+	o[files.AbsPath(filename)] = content
+}
+
+// Context returns a copy of the build context which reads
+// the files of the overlay instead of the ones on the disk
+func (o Overlay) Context(orig *build.Context) *build.Context {
+	if len(o) == 0 {
+		return orig
+	}
+
+	ctx := *orig
+	ctx.OpenFile = func(path string) (io.ReadCloser, error) {
+		if content, ok := o[files.AbsPath(path)]; ok {
+			if content == nil {
+				return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+			}
+			return ioutil.NopCloser(bytes.NewReader(content)), nil
+		}
+		if orig.OpenFile != nil {
+			return orig.OpenFile(path)
+		}
+		return os.Open(path)
+	}
+	ctx.ReadDir = func(dir string) ([]os.FileInfo, error) {
+		var infos []os.FileInfo
+		var err error
+		if orig.ReadDir != nil {
+			infos, err = orig.ReadDir(dir)
+		} else {
+			infos, err = ioutil.ReadDir(dir)
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		return o.readDir(files.AbsPath(dir), infos), nil
+	}
+	return &ctx
+}
+
+// readDir applies the overlay to the infos of the files in dir
+func (o Overlay) readDir(dir string, infos []os.FileInfo) []os.FileInfo {
+	overlaid := make([]os.FileInfo, 0, len(infos))
+	seen := map[string]bool{}
+	for _, info := range infos {
+		path := filepath.Join(dir, info.Name())
+		seen[path] = true
+
+		content, ok := o[path]
+		switch {
+		case !ok:
+			overlaid = append(overlaid, info)
+		case content != nil:
+			overlaid = append(overlaid, &fileInfo{name: info.Name(), size: int64(len(content))})
+		}
+	}
+
+	for path, content := range o {
+		if content != nil && !seen[path] && filepath.Dir(path) == dir {
+			overlaid = append(overlaid, &fileInfo{name: filepath.Base(path), size: int64(len(content))})
+		}
+	}
+
+	sort.Slice(overlaid, func(i, j int) bool {
+		return overlaid[i].Name() < overlaid[j].Name()
+	})
+	return overlaid
+}
+
+// Exists returns wether the file exists in the overlay
+// or on the disk if it is not part of the overlay
+func (o Overlay) Exists(path string) bool {
+	if content, ok := o[files.AbsPath(path)]; ok {
+		return content != nil
+	}
+	return files.FileExists(path)
+}
+
+// fileInfo is the os.FileInfo of a replaced file
+type fileInfo struct {
+	name string
+	size int64
+}
+
+func (fi *fileInfo) Name() string       { return fi.name }
+func (fi *fileInfo) Size() int64        { return fi.size }
+func (fi *fileInfo) Mode() os.FileMode  { return 0444 }
+func (fi *fileInfo) ModTime() time.Time { return time.Time{} }
+func (fi *fileInfo) IsDir() bool        { return false }
+func (fi *fileInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,52 @@
+package overlay
+
+import (
+	"go/build"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverlayContext(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "gomultilinter")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), os.ModePerm))
+		return path
+	}
+
+	write("a.go", "package foo\n")
+	write("b.go", "package foo\n")
+	write("c_linux.go", "package foo\n")
+	replacement := write("replacement", "// +build ignore\n\npackage foo\n")
+
+	overlayPath := write("overlay.json", `{"Replace": {
+		"`+filepath.Join(dir, "a.go")+`": "`+replacement+`",
+		"`+filepath.Join(dir, "c_linux.go")+`": ""
+	}}`)
+
+	o, err := ReadFile(overlayPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+	o.Add(filepath.Join(dir, "new.go"), []byte("package foo\n"))
+
+	ctx := build.Default
+	ctx.GOOS = "linux"
+	pkg, err := o.Context(&ctx).ImportDir(dir, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b.go", "new.go"}, pkg.GoFiles)
+	assert.Equal(t, []string{"a.go"}, pkg.IgnoredGoFiles)
+
+	assert.True(t, o.Exists(filepath.Join(dir, "new.go")))
+	assert.True(t, o.Exists(filepath.Join(dir, "b.go")))
+	assert.False(t, o.Exists(filepath.Join(dir, "c_linux.go")))
+}
@@ -0,0 +1,192 @@
+package checker
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/liut0/gomultilinter/api"
+	"github.com/liut0/gomultilinter/internal/checker/issue"
+)
+
+const (
+	colorReset   = "\x1b[0m"
+	colorBold    = "\x1b[1m"
+	colorRed     = "\x1b[31m"
+	colorGreen   = "\x1b[32m"
+	colorYellow  = "\x1b[33m"
+	colorBlue    = "\x1b[34m"
+	colorMagenta = "\x1b[35m"
+	colorCyan    = "\x1b[36m"
+
+	// noPositionHeader is the header of the issues without a position
+	noPositionHeader = "without position"
+)
+
+var (
+	severityColors = map[api.Severity]string{
+		api.SeverityInfo:    colorBlue,
+		api.SeverityWarning: colorYellow,
+		api.SeverityError:   colorRed,
+	}
+)
+
+// PrettyWriter implements the IssueWriter interface
+// and writes human friendly issues grouped under file headers
+// including the source line with a caret under the issue's column
+// the issues are buffered and written on Close to group them by their file
+type PrettyWriter struct {
+	out     io.Writer
+	colors  bool
+	sources *issue.Sources
+
+	issues []*issue.LinterIssue
+}
+
+func newPrettyWriter(out *os.File, sources *issue.Sources) *PrettyWriter {
+	return &PrettyWriter{
+		out:     out,
+		colors:  colorsEnabled(out),
+		sources: sources,
+	}
+}
+
+// colorsEnabled returns wether all writers are terminals
+// and colors are not disabled by NO_COLOR
+func colorsEnabled(outs ...io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	for _, out := range outs {
+		f, ok := out.(*os.File)
+		if !ok || !isTerminal(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// isTerminal returns wether the file is a terminal
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// Write buffers the issue until Close
+func (w *PrettyWriter) Write(iss *issue.LinterIssue) {
+	w.issues = append(w.issues, iss)
+}
+
+// Close writes the buffered issues grouped by their file
+// followed by the issues without a position (e.g. linter errors)
+// the order of the issues of a file is kept
+// the file is owned by the caller
+func (w *PrettyWriter) Close() error {
+	sort.SliceStable(w.issues, func(i, j int) bool {
+		return w.issues[i].Path.Abs < w.issues[j].Path.Abs
+	})
+
+	var noPosition []*issue.LinterIssue
+	lastPath := ""
+	sections := 0
+	for _, iss := range w.issues {
+		if iss.Position.Filename == "" {
+			noPosition = append(noPosition, iss)
+			continue
+		}
+
+		if sections == 0 || iss.Path.Abs != lastPath {
+			w.writeHeader(sections, iss.Path.String())
+			sections++
+			lastPath = iss.Path.Abs
+		}
+		w.writeIssue(iss)
+	}
+
+	if len(noPosition) > 0 {
+		w.writeHeader(sections, noPositionHeader)
+		for _, iss := range noPosition {
+			w.writeIssue(iss)
+		}
+	}
+
+	w.issues = nil
+	return nil
+}
+
+// writeHeader writes the header of a section
+// separated by a blank line from the previous sections
+func (w *PrettyWriter) writeHeader(previousSections int, header string) {
+	if previousSections > 0 {
+		fmt.Fprintln(w.out)
+	}
+	fmt.Fprintln(w.out, w.color(colorBold, header))
+}
+
+func (w *PrettyWriter) writeIssue(iss *issue.LinterIssue) {
+	if iss.Position.Filename == "" {
+		fmt.Fprintf(w.out, "  %s %s %s\n",
+			w.color(severityColors[iss.Severity], severityName(iss.Severity)),
+			iss.Message,
+			w.color(colorMagenta, fmt.Sprintf("(%s/%s)", iss.Linter, iss.Category)))
+	} else {
+		fmt.Fprintf(w.out, "  %s %s %s %s\n",
+			w.color(colorCyan, fmt.Sprintf("%d:%d", iss.Line(), iss.Col())),
+			w.color(severityColors[iss.Severity], severityName(iss.Severity)),
+			iss.Message,
+			w.color(colorMagenta, fmt.Sprintf("(%s/%s)", iss.Linter, iss.Category)))
+	}
+
+	if len(iss.BuildContexts) > 0 {
+		fmt.Fprintf(w.out, "    build contexts: %s\n", strings.Join(iss.BuildContexts, ", "))
+	}
+
+	if iss.Position.Filename != "" {
+		w.writeSource(iss)
+	}
+}
+
+// writeSource writes the source line of the issue
+// with a caret under the column or the range of the issue
+func (w *PrettyWriter) writeSource(iss *issue.LinterIssue) {
+	line, ok := w.sources.Line(iss.Path.Abs, iss.Line())
+	if !ok {
+		return
+	}
+	// a carriage return (CRLF line endings) would move the caret to the start of the line
+	line = strings.TrimRight(line, "\r")
+
+	lineNo := fmt.Sprintf("%d", iss.Line())
+	fmt.Fprintf(w.out, "    %s | %s\n", lineNo, line)
+
+	if iss.Col() <= 0 {
+		return
+	}
+
+	// keep tabs to align the caret with the source line
+	var indent strings.Builder
+	for i := 0; i < iss.Col()-1 && i < len(line); i++ {
+		if line[i] == '\t' {
+			indent.WriteByte('\t')
+		} else {
+			indent.WriteByte(' ')
+		}
+	}
+
+	marker := "^"
+	if iss.End.Line == iss.Line() && iss.End.Column > iss.Col()+1 {
+		marker += strings.Repeat("~", iss.End.Column-iss.Col()-1)
+	}
+
+	fmt.Fprintf(w.out, "    %s | %s%s\n", strings.Repeat(" ", len(lineNo)), indent.String(), w.color(colorGreen, marker))
+}
+
+func (w *PrettyWriter) color(color, s string) string {
+	if !w.colors || color == "" {
+		return s
+	}
+	return color + s + colorReset
+}
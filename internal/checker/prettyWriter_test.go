@@ -0,0 +1,157 @@
+package checker
+
+import (
+	"bytes"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liut0/gomultilinter/api"
+	"github.com/liut0/gomultilinter/internal/checker/issue"
+	"github.com/liut0/gomultilinter/internal/checker/overlay"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrettyWriter(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "gomultilinter")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "foo.go")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("package foo\n\nfunc Foo() {\n\tbar := baz()\n}\n"), os.ModePerm))
+
+	var out bytes.Buffer
+	w := &PrettyWriter{out: &out}
+
+	w.Write(issue.ToLinterIssue(&api.Issue{
+		Position: token.Position{Filename: path, Line: 3, Column: 6},
+		Severity: api.SeverityWarning,
+		Category: "comments",
+		Message:  "exported function Foo should have comment",
+	}, "golint"))
+	w.Write(issue.ToLinterIssue(&api.Issue{
+		Position: token.Position{Filename: path, Line: 4, Column: 9},
+		End:      token.Position{Filename: path, Line: 4, Column: 14},
+		Severity: api.SeverityError,
+		Category: "unchecked",
+		Message:  "error not checked",
+	}, "errcheck"))
+
+	assert.Empty(t, out.String())
+	assert.NoError(t, w.Close())
+
+	rel := issue.ToLinterIssue(&api.Issue{Position: token.Position{Filename: path}}, "").Path.String()
+	assert.Equal(t, rel+`
+  3:6 warning exported function Foo should have comment (golint/comments)
+    3 | func Foo() {
+      |      ^
+  4:9 error error not checked (errcheck/unchecked)
+    4 | `+"\tbar := baz()"+`
+      | `+"\t"+`       ^~~~~
+`, out.String())
+}
+
+func TestPrettyWriterGroupsFiles(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	w := &PrettyWriter{out: &out}
+
+	for _, pos := range []token.Position{
+		{Filename: "/src/b.go", Line: 3},
+		{Filename: "/src/a.go", Line: 7},
+		{Filename: "/src/b.go", Line: 1},
+		{Filename: "/src/a.go", Line: 2},
+	} {
+		w.Write(issue.ToLinterIssue(&api.Issue{
+			Position: pos,
+			Severity: api.SeverityInfo,
+			Category: "cat",
+			Message:  "msg",
+		}, "vet"))
+	}
+	assert.NoError(t, w.Close())
+
+	a := issue.ToLinterIssue(&api.Issue{Position: token.Position{Filename: "/src/a.go"}}, "").Path.String()
+	b := issue.ToLinterIssue(&api.Issue{Position: token.Position{Filename: "/src/b.go"}}, "").Path.String()
+	assert.Equal(t, a+`
+  7:0 info msg (vet/cat)
+  2:0 info msg (vet/cat)
+
+`+b+`
+  3:0 info msg (vet/cat)
+  1:0 info msg (vet/cat)
+`, out.String())
+}
+
+func TestPrettyWriterOverlay(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "gomultilinter")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "foo.go")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("package foo\n\nfunc Foo() {}\n"), os.ModePerm))
+
+	var out bytes.Buffer
+	w := &PrettyWriter{out: &out, sources: issue.NewSources(nil)}
+	w.sources.SetOverlay(overlay.Overlay{path: []byte("package foo\n\n// Foo foos\nfunc Foo() {}\n")})
+
+	w.Write(issue.ToLinterIssue(&api.Issue{
+		Position: token.Position{Filename: path, Line: 4, Column: 6},
+		Severity: api.SeverityWarning,
+		Category: "naming",
+		Message:  "Foo is a bad name",
+	}, "golint"))
+	assert.NoError(t, w.Close())
+
+	rel := issue.ToLinterIssue(&api.Issue{Position: token.Position{Filename: path}}, "").Path.String()
+	assert.Equal(t, rel+`
+  4:6 warning Foo is a bad name (golint/naming)
+    4 | func Foo() {}
+      |      ^
+`, out.String())
+}
+
+func TestPrettyWriterCRLFAndNoPosition(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "gomultilinter")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "foo.go")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("package foo\r\n\r\nfunc Foo() {}\r"), os.ModePerm))
+
+	var out bytes.Buffer
+	w := &PrettyWriter{out: &out}
+
+	w.Write(issue.ToLinterIssue(&api.Issue{
+		Severity: api.SeverityError,
+		Category: "linter-error",
+		Message:  "linter returned error: boom",
+	}, "vet"))
+	w.Write(issue.ToLinterIssue(&api.Issue{
+		Position: token.Position{Filename: path, Line: 3, Column: 6},
+		End:      token.Position{Filename: path, Line: 3, Column: 9},
+		Severity: api.SeverityWarning,
+		Category: "comments",
+		Message:  "exported function Foo should have comment",
+	}, "golint"))
+	assert.NoError(t, w.Close())
+
+	rel := issue.ToLinterIssue(&api.Issue{Position: token.Position{Filename: path}}, "").Path.String()
+	assert.Equal(t, rel+`
+  3:6 warning exported function Foo should have comment (golint/comments)
+    3 | func Foo() {}
+      |      ^~~
+`+`
+without position
+  error linter returned error: boom (vet/linter-error)
+`, out.String())
+}
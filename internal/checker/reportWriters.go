@@ -0,0 +1,244 @@
+package checker
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/liut0/gomultilinter/api"
+	"github.com/liut0/gomultilinter/internal/checker/issue"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// jsonWriter implements the IssueWriter interface
+// and writes all issues as json array on Close
+type jsonWriter struct {
+	out    io.Writer
+	issues []*jsonIssue
+}
+
+type jsonIssue struct {
+	Path          string   `json:"path"`
+	Line          int      `json:"line"`
+	Column        int      `json:"column"`
+	EndLine       int      `json:"end_line,omitempty"`
+	EndColumn     int      `json:"end_column,omitempty"`
+	Severity      string   `json:"severity"`
+	Category      string   `json:"category"`
+	Message       string   `json:"message"`
+	Linter        string   `json:"linter"`
+	BuildContexts []string `json:"build_contexts,omitempty"`
+}
+
+func (w *jsonWriter) Write(iss *issue.LinterIssue) {
+	w.issues = append(w.issues, &jsonIssue{
+		Path:          iss.Path.String(),
+		Line:          iss.Line(),
+		Column:        iss.Col(),
+		EndLine:       iss.End.Line,
+		EndColumn:     iss.End.Column,
+		Severity:      severityName(iss.Severity),
+		Category:      iss.Category,
+		Message:       iss.Message,
+		Linter:        iss.Linter,
+		BuildContexts: iss.BuildContexts,
+	})
+}
+
+func (w *jsonWriter) Close() error {
+	if w.issues == nil {
+		w.issues = []*jsonIssue{}
+	}
+
+	enc := json.NewEncoder(w.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(w.issues)
+}
+
+// checkstyleWriter implements the IssueWriter interface
+// and writes all issues as checkstyle xml report on Close
+type checkstyleWriter struct {
+	out   io.Writer
+	files []*checkstyleFile
+	index map[string]*checkstyleFile
+}
+
+type checkstyleReport struct {
+	XMLName xml.Name          `xml:"checkstyle"`
+	Version string            `xml:"version,attr"`
+	Files   []*checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string             `xml:"name,attr"`
+	Errors []*checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+func (w *checkstyleWriter) Write(iss *issue.LinterIssue) {
+	if w.index == nil {
+		w.index = map[string]*checkstyleFile{}
+	}
+
+	f, ok := w.index[iss.Path.Abs]
+	if !ok {
+		f = &checkstyleFile{Name: iss.Path.String()}
+		w.index[iss.Path.Abs] = f
+		w.files = append(w.files, f)
+	}
+
+	f.Errors = append(f.Errors, &checkstyleError{
+		Line:     iss.Line(),
+		Column:   iss.Col(),
+		Severity: severityName(iss.Severity),
+		Message:  iss.Message,
+		Source:   iss.Linter + "." + iss.Category,
+	})
+}
+
+func (w *checkstyleWriter) Close() error {
+	if _, err := io.WriteString(w.out, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w.out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(&checkstyleReport{Version: "5.0", Files: w.files}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w.out, "\n")
+	return err
+}
+
+// sarifWriter implements the IssueWriter interface
+// and writes all issues as SARIF log on Close
+type sarifWriter struct {
+	out     io.Writer
+	results []*sarifResult
+}
+
+type sarifLog struct {
+	Version string      `json:"version"`
+	Schema  string      `json:"$schema"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool      `json:"tool"`
+	Results []*sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string `json:"name"`
+	InformationURI string `json:"informationUri"`
+}
+
+type sarifResult struct {
+	RuleID    string           `json:"ruleId"`
+	Level     string           `json:"level"`
+	Message   sarifMessage     `json:"message"`
+	Locations []*sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+var (
+	sarifLevels = map[api.Severity]string{
+		api.SeverityInfo:    "note",
+		api.SeverityWarning: "warning",
+		api.SeverityError:   "error",
+	}
+)
+
+func (w *sarifWriter) Write(iss *issue.LinterIssue) {
+	result := &sarifResult{
+		RuleID:    iss.Linter + "/" + iss.Category,
+		Level:     sarifLevels[iss.Severity],
+		Message:   sarifMessage{Text: iss.Message},
+		Locations: []*sarifLocation{},
+	}
+
+	if iss.Position.Filename != "" {
+		location := &sarifLocation{
+			PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(iss.Path.String())},
+			},
+		}
+		if iss.Line() > 0 {
+			location.PhysicalLocation.Region = &sarifRegion{
+				StartLine:   iss.Line(),
+				StartColumn: iss.Col(),
+				EndLine:     iss.End.Line,
+				EndColumn:   iss.End.Column,
+			}
+		}
+		result.Locations = append(result.Locations, location)
+	}
+
+	w.results = append(w.results, result)
+}
+
+func (w *sarifWriter) Close() error {
+	results := w.results
+	if results == nil {
+		results = []*sarifResult{}
+	}
+
+	enc := json.NewEncoder(w.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(&sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []*sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           selfLinterName,
+				InformationURI: "https://github.com/liut0/gomultilinter",
+			}},
+			Results: results,
+		}},
+	})
+}
+
+// severityName returns the lower case name of the severity
+func severityName(severity api.Severity) string {
+	return strings.ToLower(severity.String())
+}
@@ -4,32 +4,55 @@ import (
 	"sync"
 
 	"github.com/liut0/gomultilinter/api"
+	"github.com/liut0/gomultilinter/config"
 	"github.com/liut0/gomultilinter/internal/checker/filter"
 	"github.com/liut0/gomultilinter/internal/checker/issue"
 	"github.com/liut0/gomultilinter/internal/log"
 )
 
 // IssueReporter implements the api.Reporter interface
-// and filters/collects issues and passes them to the writer
+// and filters/collects issues
+// issues which are reported in multiple build contexts are collected once
 type IssueReporter struct {
-	filter filter.IssueFilter
+	severityRules []*config.SeverityRule
+	filter        filter.IssueFilter
+
+	// write is called for each collected issue if issues are streamed
+	// nil if they are written at the end of the run
+	write func(*issue.LinterIssue)
 
 	allIssuesLock sync.Mutex
 	allIssues     []*issue.LinterIssue
+	issueIndex    map[issueKey]*issue.LinterIssue
+}
 
-	issueWriter IssueWriter
+// issueKey identifies an issue across build contexts
+type issueKey struct {
+	linter   string
+	filename string
+	line     int
+	column   int
+	severity api.Severity
+	category string
+	message  string
 }
 
 // IssueReporterEntry is a concrete reporter of a linter's invocation
 type IssueReporterEntry struct {
 	*IssueReporter
-	linter string
+	linter       string
+	buildContext string
+	pkg          string
 }
 
-func (r *IssueReporter) entry(linter string) *IssueReporterEntry {
+// entry returns a reporter of the linter linting the package (import path) in the build context
+// buildContext is empty if only a single build context is linted
+func (r *IssueReporter) entry(linter, buildContext, pkg string) *IssueReporterEntry {
 	return &IssueReporterEntry{
 		IssueReporter: r,
 		linter:        linter,
+		buildContext:  buildContext,
+		pkg:           pkg,
 	}
 }
 
@@ -39,25 +62,79 @@ func (r *IssueReporterEntry) Debug(msg string, fields ...interface{}) {
 	log.WithFields(fields...).Debug(msg)
 }
 
-// Report checks if an issue gets filtered
+// Report remaps the severity of the issue and checks if it gets filtered
 // if so the issue is ignored
-// otherwise it adds the issue to the list of all issues and
-// passes it to the writer
+// otherwise it adds the issue to the list of all issues
 func (r *IssueReporterEntry) Report(iss *api.Issue) {
 	linterIssue := issue.ToLinterIssue(iss, r.linter)
+	linterIssue.Package = r.pkg
+
+	r.remapSeverity(linterIssue)
 
 	if r.filter.IgnoreIssue(linterIssue) {
 		return
 	}
 
 	r.addIssue(linterIssue)
+}
 
-	r.issueWriter.Write(linterIssue)
+// remapSeverity applies the first matching severity rule
+// the api.Issue is copied since it is owned by the linter
+func (r *IssueReporterEntry) remapSeverity(linterIssue *issue.LinterIssue) {
+	for _, rule := range r.severityRules {
+		if rule.Severity == nil ||
+			!rule.Matches(linterIssue.Path.Abs, linterIssue.Linter, linterIssue.Category, linterIssue.Message) {
+			continue
+		}
+
+		if rule.Severity.Severity != linterIssue.Severity {
+			remapped := *linterIssue.Issue
+			remapped.Severity = rule.Severity.Severity
+			linterIssue.Issue = &remapped
+		}
+		return
+	}
 }
 
-func (r *IssueReporterEntry) addIssue(issue *issue.LinterIssue) {
+// addIssue adds the issue to the list of all issues
+// if the same issue was already reported in another build context
+// only the build context is added to it
+func (r *IssueReporterEntry) addIssue(linterIssue *issue.LinterIssue) {
 	r.allIssuesLock.Lock()
 	defer r.allIssuesLock.Unlock()
 
-	r.allIssues = append(r.allIssues, issue)
+	if r.buildContext == "" {
+		r.allIssues = append(r.allIssues, linterIssue)
+		if r.write != nil {
+			r.write(linterIssue)
+		}
+		return
+	}
+
+	key := issueKey{
+		linter:   linterIssue.Linter,
+		filename: linterIssue.Path.Abs,
+		line:     linterIssue.Line(),
+		column:   linterIssue.Col(),
+		severity: linterIssue.Severity,
+		category: linterIssue.Category,
+		message:  linterIssue.Message,
+	}
+
+	if existing, ok := r.issueIndex[key]; ok {
+		for _, ctx := range existing.BuildContexts {
+			if ctx == r.buildContext {
+				return
+			}
+		}
+		existing.BuildContexts = append(existing.BuildContexts, r.buildContext)
+		return
+	}
+
+	if r.issueIndex == nil {
+		r.issueIndex = map[issueKey]*issue.LinterIssue{}
+	}
+	linterIssue.BuildContexts = []string{r.buildContext}
+	r.issueIndex[key] = linterIssue
+	r.allIssues = append(r.allIssues, linterIssue)
 }
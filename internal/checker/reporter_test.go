@@ -0,0 +1,94 @@
+package checker
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/liut0/gomultilinter/api"
+	"github.com/liut0/gomultilinter/config"
+	"github.com/liut0/gomultilinter/internal/checker/filter"
+	"github.com/liut0/gomultilinter/internal/checker/issue"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueReporterSeverityRules(t *testing.T) {
+	t.Parallel()
+
+	r := &IssueReporter{
+		severityRules: []*config.SeverityRule{
+			{
+				IssueMatcher: config.IssueMatcher{
+					Linter:   &config.Regex{Regexp: regexp.MustCompile(`^errcheck$`)},
+					Category: &config.Regex{Regexp: regexp.MustCompile(`^unchecked$`)},
+				},
+				Severity: &config.Severity{Severity: api.SeverityError},
+			},
+			{
+				IssueMatcher: config.IssueMatcher{
+					Linter: &config.Regex{Regexp: regexp.MustCompile(`^errcheck$`)},
+				},
+				Severity: &config.Severity{Severity: api.SeverityInfo},
+			},
+		},
+		filter: filter.SeverityFilter(api.SeverityWarning),
+	}
+
+	iss := &api.Issue{Category: "unchecked", Severity: api.SeverityWarning}
+	r.entry("errcheck", "", "foo").Report(iss)
+	r.entry("errcheck", "", "foo").Report(&api.Issue{Category: "blank", Severity: api.SeverityWarning})
+	r.entry("golint", "", "foo").Report(&api.Issue{Category: "unchecked", Severity: api.SeverityWarning})
+
+	assert.Len(t, r.allIssues, 2)
+	assert.Equal(t, api.SeverityError, r.allIssues[0].Severity)
+	assert.Equal(t, api.SeverityWarning, r.allIssues[1].Severity)
+	assert.Equal(t, "golint", r.allIssues[1].Linter)
+
+	// the linter's issue must not be modified
+	assert.Equal(t, api.SeverityWarning, iss.Severity)
+}
+
+func TestIssueReporterBuildContexts(t *testing.T) {
+	t.Parallel()
+
+	r := &IssueReporter{filter: filter.SeverityFilter(api.SeverityInfo)}
+
+	newIssue := func(line int) *api.Issue {
+		iss := &api.Issue{Category: "unchecked", Message: "unchecked error", Severity: api.SeverityWarning}
+		iss.Position.Filename = "/src/foo/foo.go"
+		iss.Position.Line = line
+		return iss
+	}
+
+	r.entry("errcheck", "linux/amd64", "foo").Report(newIssue(3))
+	r.entry("errcheck", "linux/amd64", "foo").Report(newIssue(5))
+	r.entry("errcheck", "windows/amd64", "foo").Report(newIssue(3))
+	r.entry("golint", "windows/amd64", "foo").Report(newIssue(3))
+	r.entry("errcheck", "windows/amd64", "foo").Report(newIssue(3))
+
+	if assert.Len(t, r.allIssues, 3) {
+		assert.Equal(t, []string{"linux/amd64", "windows/amd64"}, r.allIssues[0].BuildContexts)
+		assert.Equal(t, []string{"linux/amd64"}, r.allIssues[1].BuildContexts)
+		assert.Equal(t, []string{"windows/amd64"}, r.allIssues[2].BuildContexts)
+	}
+}
+
+func TestIssueReporterStream(t *testing.T) {
+	t.Parallel()
+
+	var written []*issue.LinterIssue
+	r := &IssueReporter{
+		filter: filter.SeverityFilter(api.SeverityWarning),
+		write: func(iss *issue.LinterIssue) {
+			written = append(written, iss)
+		},
+	}
+
+	r.entry("golint", "", "foo").Report(&api.Issue{Severity: api.SeverityWarning, Message: "a"})
+	assert.Len(t, written, 1)
+
+	r.entry("golint", "", "foo").Report(&api.Issue{Severity: api.SeverityInfo, Message: "b"})
+	assert.Len(t, written, 1)
+
+	r.entry("golint", "", "foo").Report(&api.Issue{Severity: api.SeverityError, Message: "c"})
+	assert.Equal(t, r.allIssues, written)
+}
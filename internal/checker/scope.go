@@ -0,0 +1,101 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/liut0/gomultilinter/api"
+	"github.com/liut0/gomultilinter/config"
+	"github.com/liut0/gomultilinter/internal/checker/filter"
+	"github.com/liut0/gomultilinter/internal/checker/issue"
+	"github.com/liut0/gomultilinter/internal/log"
+)
+
+// names of the filters in the suppression stats
+const (
+	suppressedByTargets      = "targets"
+	suppressedBySeverity     = "severity"
+	suppressedByCategory     = "category"
+	suppressedByName         = "name"
+	suppressedByPath         = "path"
+	suppressedByMessage      = "message"
+	suppressedByExcludeRules = "exclude_rules"
+	suppressedByNoLint       = "nolint"
+)
+
+// checkerFilters are the filters which are shared by all scopes
+type checkerFilters struct {
+	excludeRules *filter.ExcludeRulesFilter
+	targetFiles  *filter.TargetFilesFilter
+	suppressions *filter.SuppressionCounter
+}
+
+// scope holds the linters and filters of a config
+// which apply to the packages inside of the config's directory
+type scope struct {
+	excludeUnnecessaryNoLintDirectives bool
+	excludeNames                       config.MultiRegex
+	excludePaths                       config.MultiGlob
+	generated                          *config.GeneratedConfig
+	skipTypeErrors                     bool
+	targetFiles                        *filter.TargetFilesFilter
+
+	fileLinter map[string]api.FileLinter
+	pkgLinter  map[string]api.PackageLinter
+
+	issueReporter           *IssueReporter
+	selfIssueReporter       api.IssueReporter
+	noLinterDirectiveFilter *filter.NoLinterDirectiveFilter
+}
+
+// newScope constructs the scope of the config
+// write is called for each collected issue if issues are streamed, otherwise nil
+func newScope(conf *config.Config, linter []api.Linter, filters *checkerFilters, write func(*issue.LinterIssue)) (*scope, error) {
+	noLinterDirectiveFilter := &filter.NoLinterDirectiveFilter{}
+	counted := filters.suppressions.Counted
+
+	reporter := &IssueReporter{
+		severityRules: conf.SeverityRules,
+		write:         write,
+
+		filter: filter.ChainFilter(
+			counted(suppressedByTargets, filters.targetFiles),
+			counted(suppressedBySeverity, filter.SeverityFilter(conf.MinSeverity.Severity)),
+			counted(suppressedByCategory, filter.CategoryFilter(conf.Exclude.Categories)),
+			// filter names again (pkglinters cant filter filenames before linting)
+			counted(suppressedByName, filter.FilenameFilter(conf.Exclude.Names)),
+			counted(suppressedByPath, filter.PathFilter(conf.Exclude.Paths)),
+			counted(suppressedByMessage, filter.MessageFilter(conf.Exclude.Messages)),
+			counted(suppressedByExcludeRules, filters.excludeRules.Filter(conf.Exclude.Rules)),
+			counted(suppressedByNoLint, noLinterDirectiveFilter)),
+	}
+
+	s := &scope{
+		excludeUnnecessaryNoLintDirectives: conf.Exclude.UnnecessaryNoLintDirectives,
+		excludeNames:                       conf.Exclude.Names,
+		excludePaths:                       conf.Exclude.Paths,
+		generated:                          conf.Generated,
+		skipTypeErrors:                     conf.SkipTypeErrors,
+		targetFiles:                        filters.targetFiles,
+
+		fileLinter: map[string]api.FileLinter{},
+		pkgLinter:  map[string]api.PackageLinter{},
+
+		issueReporter:           reporter,
+		selfIssueReporter:       reporter.entry(selfLinterName, "", ""),
+		noLinterDirectiveFilter: noLinterDirectiveFilter,
+	}
+
+	for _, l := range linter {
+		switch lT := l.(type) {
+		case api.FileLinter:
+			s.fileLinter[lT.Name()] = lT
+		case api.PackageLinter:
+			s.pkgLinter[lT.Name()] = lT
+		default:
+			log.WithFields("linter", l.Name()).Debug("unsupported linter")
+			return nil, fmt.Errorf("unsupported linter %v", l.Name())
+		}
+	}
+
+	return s, nil
+}
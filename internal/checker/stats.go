@@ -0,0 +1,35 @@
+package checker
+
+// Stats are statistics of a run
+type Stats struct {
+	// Packages is the number of analysed packages
+	Packages int
+
+	// Files is the number of analysed files
+	Files int
+
+	// Suppressed is the number of filtered out issues per filter
+	// (targets, severity, category, name, path, message, exclude_rules, nolint)
+	Suppressed map[string]int
+
+	// Hidden is the number of issues hidden by the max issues limits
+	Hidden int
+
+	// LinterErrors is the number of returned errors per linter
+	LinterErrors map[string]int
+
+	// LinterPanics is the number of panics per linter
+	LinterPanics map[string]int
+}
+
+// Stats returns the statistics of the run
+func (c *Checker) Stats() *Stats {
+	return &Stats{
+		Packages:     len(c.analysedPkgs),
+		Files:        len(c.analysedFiles),
+		Suppressed:   c.filters.suppressions.Counts(),
+		Hidden:       c.hiddenIssues,
+		LinterErrors: c.linterErrors,
+		LinterPanics: c.linterPanics,
+	}
+}
@@ -0,0 +1,95 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/liut0/gomultilinter/internal/checker/issue"
+	"github.com/liut0/gomultilinter/internal/files"
+)
+
+var (
+	templateColors = map[string]string{
+		"bold":    colorBold,
+		"red":     colorRed,
+		"green":   colorGreen,
+		"yellow":  colorYellow,
+		"blue":    colorBlue,
+		"magenta": colorMagenta,
+		"cyan":    colorCyan,
+	}
+)
+
+// templateFuncs returns the functions which are available in the output templates
+// color only colors the text if colors is true,
+// sourceLine reads the lines through the sources
+func templateFuncs(colors bool, sources *issue.Sources) template.FuncMap {
+	return template.FuncMap{
+		// lower returns the lower case string of the value
+		"lower": func(v interface{}) string {
+			return strings.ToLower(fmt.Sprint(v))
+		},
+		// upper returns the upper case string of the value
+		"upper": func(v interface{}) string {
+			return strings.ToUpper(fmt.Sprint(v))
+		},
+		// json returns the value as json, fmt.Stringers are encoded as json string
+		"json": func(v interface{}) (string, error) {
+			if s, ok := v.(fmt.Stringer); ok {
+				v = s.String()
+			}
+			content, err := json.Marshal(v)
+			return string(content), err
+		},
+		// pad pads the string of the value with spaces to the width
+		// a negative width pads on the left
+		"pad": func(width int, v interface{}) string {
+			return fmt.Sprintf("%*s", -width, fmt.Sprint(v))
+		},
+		// relTo returns the path relative to the root directory
+		"relTo": func(root string, path interface{}) string {
+			abs := files.AbsPath(fmt.Sprint(path))
+			rel, err := filepath.Rel(files.AbsPath(root), abs)
+			if err != nil {
+				return abs
+			}
+			return rel
+		},
+		// basename returns the last element of the path
+		"basename": func(path interface{}) string {
+			return filepath.Base(fmt.Sprint(path))
+		},
+		// color colors the string of the value (bold, red, green, yellow, blue, magenta, cyan)
+		"color": func(color string, v interface{}) (string, error) {
+			code, ok := templateColors[color]
+			if !ok {
+				return "", fmt.Errorf("unknown color %s", color)
+			}
+			if !colors {
+				return fmt.Sprint(v), nil
+			}
+			return code + fmt.Sprint(v) + colorReset, nil
+		},
+		// sourceLine returns the line (starting at 1) of the file
+		"sourceLine": func(path interface{}, line int) string {
+			src, _ := sources.Line(files.AbsPath(fmt.Sprint(path)), line)
+			return src
+		},
+	}
+}
+
+// templateIssue is the issue passed to the output templates
+type templateIssue struct {
+	*issue.LinterIssue
+	sources *issue.Sources
+}
+
+// SourceLine returns the source line of the issue
+// empty if it could not be read
+func (t *templateIssue) SourceLine() string {
+	line, _ := t.sources.Line(t.Path.Abs, t.Line())
+	return line
+}
@@ -0,0 +1,68 @@
+package checker
+
+import (
+	"bytes"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/liut0/gomultilinter/api"
+	"github.com/liut0/gomultilinter/internal/checker/issue"
+	"github.com/liut0/gomultilinter/internal/checker/overlay"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "gomultilinter")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "pkg", "foo.go")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+	assert.NoError(t, ioutil.WriteFile(path, []byte("package foo\n\nvar baz = 2\n"), os.ModePerm))
+
+	// the source lines are read from the overlay instead of the file on the disk
+	sources := issue.NewSources(overlay.Overlay{path: []byte("package foo\n\nvar bar = 1\n")})
+
+	iss := issue.ToLinterIssue(&api.Issue{
+		Position: token.Position{Filename: path, Line: 3, Column: 5},
+		Severity: api.SeverityWarning,
+		Category: "unused",
+		Message:  `"bar" is unused`,
+	}, "vet")
+	iss.Package = "example.com/pkg"
+	tmplIss := &templateIssue{LinterIssue: iss, sources: sources}
+
+	cases := map[string]string{
+		`{{lower .Severity}} {{upper .Linter}}`:                   "warning VET",
+		`{{json .Message}} {{json .Severity}}`:                    `"\"bar\" is unused" "Warning"`,
+		`[{{pad 5 .Linter}}] [{{pad -5 .Line}}]`:                  "[vet  ] [    3]",
+		`{{relTo "` + dir + `" .Path.Abs}} {{basename .Path}}`:    filepath.Join("pkg", "foo.go") + " foo.go",
+		`{{color "red" .Linter}}`:                                 "vet",
+		`{{sourceLine .Path .Line}}|{{.SourceLine}}|{{.Package}}`: "var bar = 1|var bar = 1|example.com/pkg",
+	}
+
+	for tmplStr, expected := range cases {
+		tmpl, err := template.New("").Funcs(templateFuncs(false, sources)).Parse(tmplStr)
+		if !assert.NoError(t, err, tmplStr) {
+			continue
+		}
+
+		var out bytes.Buffer
+		assert.NoError(t, tmpl.Execute(&out, tmplIss), tmplStr)
+		assert.Equal(t, expected, out.String(), tmplStr)
+	}
+
+	tmpl := template.Must(template.New("").Funcs(templateFuncs(true, sources)).Parse(`{{color "red" .Linter}}`))
+	var out bytes.Buffer
+	assert.NoError(t, tmpl.Execute(&out, tmplIss))
+	assert.Equal(t, colorRed+"vet"+colorReset, out.String())
+
+	tmpl = template.Must(template.New("").Funcs(templateFuncs(true, sources)).Parse(`{{color "pink" .Linter}}`))
+	assert.Error(t, tmpl.Execute(&out, tmplIss))
+}
@@ -0,0 +1,56 @@
+package checker
+
+import (
+	"go/scanner"
+	"go/token"
+	"go/types"
+
+	"github.com/liut0/gomultilinter/api"
+	"golang.org/x/tools/go/loader"
+)
+
+const (
+	typeCheckCategory = "typecheck"
+)
+
+// reportTypeErrors reports the parse and type check errors of the package
+// returns wether the package has any errors
+func (c *Checker) reportTypeErrors(s *scope, pkgInfo *loader.PackageInfo) bool {
+	if len(pkgInfo.Errors) == 0 {
+		return false
+	}
+
+	r := s.issueReporter.entry(selfLinterName, c.buildContext, pkgInfo.Pkg.Path())
+	for _, err := range pkgInfo.Errors {
+		for _, iss := range typeErrorIssues(err) {
+			r.Report(iss)
+		}
+	}
+	return true
+}
+
+func typeErrorIssues(err error) []*api.Issue {
+	switch errT := err.(type) {
+	case types.Error:
+		return []*api.Issue{newTypeErrorIssue(errT.Fset.Position(errT.Pos), errT.Msg)}
+	case scanner.ErrorList:
+		issues := make([]*api.Issue, 0, len(errT))
+		for _, scanErr := range errT {
+			issues = append(issues, newTypeErrorIssue(scanErr.Pos, scanErr.Msg))
+		}
+		return issues
+	case *scanner.Error:
+		return []*api.Issue{newTypeErrorIssue(errT.Pos, errT.Msg)}
+	default:
+		return []*api.Issue{newTypeErrorIssue(token.Position{}, err.Error())}
+	}
+}
+
+func newTypeErrorIssue(pos token.Position, msg string) *api.Issue {
+	return &api.Issue{
+		Position: pos,
+		Severity: api.SeverityError,
+		Category: typeCheckCategory,
+		Message:  msg,
+	}
+}
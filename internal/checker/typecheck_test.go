@@ -0,0 +1,44 @@
+package checker
+
+import (
+	"errors"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/liut0/gomultilinter/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeErrorIssues(t *testing.T) {
+	t.Parallel()
+
+	fset := token.NewFileSet()
+	file := fset.AddFile("/src/foo/foo.go", -1, 100)
+	file.SetLines([]int{0, 20, 40})
+
+	issues := typeErrorIssues(types.Error{Fset: fset, Pos: file.Pos(25), Msg: "undeclared name: bar"})
+	if assert.Len(t, issues, 1) {
+		assert.Equal(t, "/src/foo/foo.go", issues[0].Position.Filename)
+		assert.Equal(t, 2, issues[0].Position.Line)
+		assert.Equal(t, 6, issues[0].Position.Column)
+		assert.Equal(t, "undeclared name: bar", issues[0].Message)
+		assert.Equal(t, typeCheckCategory, issues[0].Category)
+		assert.Equal(t, api.SeverityError, issues[0].Severity)
+	}
+
+	var errList scanner.ErrorList
+	errList.Add(token.Position{Filename: "/src/foo/foo.go", Line: 1, Column: 1}, "expected 'package'")
+	errList.Add(token.Position{Filename: "/src/foo/foo.go", Line: 3, Column: 2}, "expected ';'")
+	issues = typeErrorIssues(errList)
+	if assert.Len(t, issues, 2) {
+		assert.Equal(t, 3, issues[1].Position.Line)
+		assert.Equal(t, "expected ';'", issues[1].Message)
+	}
+
+	issues = typeErrorIssues(errors.New("could not import bar"))
+	if assert.Len(t, issues, 1) {
+		assert.Equal(t, "could not import bar", issues[0].Message)
+	}
+}
@@ -6,30 +6,39 @@ import (
 	"regexp"
 
 	"github.com/liut0/gomultilinter/api"
+	"github.com/liut0/gomultilinter/internal/log"
 	"golang.org/x/tools/go/loader"
 )
 
 var (
-	generatedFileRgx = regexp.MustCompile("(?si).*code generated.*do not edit.*")
+	// see https://golang.org/s/generatedcode
+	generatedFileRgx = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
 )
 
 func (c *Checker) walkPkgs(pkgInfos []*loader.PackageInfo, fset *token.FileSet) {
 	for _, pkgInfo := range pkgInfos {
-		c.walkPkg(pkgInfo, fset)
+		c.walkPkg(c.pkgScopes[pkgInfo], pkgInfo, fset)
 	}
 }
 
-func (c *Checker) walkPkg(pkgInfo *loader.PackageInfo, fset *token.FileSet) {
+func (c *Checker) walkPkg(s *scope, pkgInfo *loader.PackageInfo, fset *token.FileSet) {
 
 	pkg := &api.Package{
 		PkgInfo: pkgInfo,
 		FSet:    fset,
 	}
 
-	if c.ignorePkg(pkg) {
+	if s.ignorePkg(pkg) {
 		return
 	}
 
+	if c.reportTypeErrors(s, pkgInfo) && s.skipTypeErrors {
+		log.WithFields("pkg", pkgInfo.Pkg.Path()).Debug("skipping package with type errors")
+		return
+	}
+
+	c.analysedPkgs[pkgInfo.Pkg.Path()] = true
+
 	files := make([]*api.File, 0, len(pkgInfo.Files))
 
 	for _, astFile := range pkgInfo.Files {
@@ -41,32 +50,60 @@ func (c *Checker) walkPkg(pkgInfo *loader.PackageInfo, fset *token.FileSet) {
 			CommentMap: ast.NewCommentMap(pkg.FSet, astFile, astFile.Comments),
 		}
 
-		if !c.ignoreFile(file) {
-			c.noLinterDirectiveFilter.AddFile(file)
+		if !s.ignoreFile(file) {
+			c.analysedFiles[file.Position.Filename] = true
+			s.noLinterDirectiveFilter.AddFile(file)
 			files = append(files, file)
 		}
 	}
 
-	c.lintPkg(pkg)
+	c.metrics.measurePackage(pkgInfo.Pkg.Path(), func() {
+		c.lintPkg(s, pkg)
 
-	for _, f := range files {
-		c.lintFile(f)
-	}
+		for _, f := range files {
+			c.lintFile(s, f)
+		}
+	})
 }
 
-func (c *Checker) ignorePkg(pkg *api.Package) bool {
-	return c.excludeNames.MatchesAny(pkg.PkgInfo.Pkg.Path())
+func (s *scope) ignorePkg(pkg *api.Package) bool {
+	return s.excludeNames.MatchesAny(pkg.PkgInfo.Pkg.Path())
 }
 
-func (c *Checker) ignoreFile(file *api.File) bool {
+func (s *scope) ignoreFile(file *api.File) bool {
+
+	if s.targetFiles.IgnoreFile(file.Position.Filename) {
+		return true
+	}
+
+	if s.excludeNames.MatchesAny(file.Position.Filename) || s.excludePaths.MatchesAny(file.Position.Filename) {
+		return true
+	}
+
+	if !s.generated.Lint && s.isGenerated(file) {
+		log.WithFields("file", file.Position.Filename).Debug("skipping generated file")
+		return true
+	}
 
-	if c.excludeNames.MatchesAny(file.Position.Filename) {
+	return false
+}
+
+// isGenerated returns wether the file is generated, either by its path
+// or by a line comment before the package clause
+func (s *scope) isGenerated(file *api.File) bool {
+	if s.generated.Paths.MatchesAny(file.Position.Filename) {
 		return true
 	}
 
-	for _, cmnt := range file.ASTFile.Comments {
-		if generatedFileRgx.MatchString(cmnt.Text()) {
-			return true
+	for _, cmntGrp := range file.ASTFile.Comments {
+		if cmntGrp.Pos() >= file.ASTFile.Package {
+			break
+		}
+
+		for _, cmnt := range cmntGrp.List {
+			if generatedFileRgx.MatchString(cmnt.Text) || s.generated.Patterns.MatchesAny(cmnt.Text) {
+				return true
+			}
 		}
 	}
 	return false
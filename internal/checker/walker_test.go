@@ -0,0 +1,47 @@
+package checker
+
+import (
+	"go/parser"
+	"go/token"
+	"regexp"
+	"testing"
+
+	"github.com/liut0/gomultilinter/api"
+	"github.com/liut0/gomultilinter/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func parseTestFile(t *testing.T, filename, src string) *api.File {
+	fset := token.NewFileSet()
+	astFile, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	pos := fset.Position(astFile.Pos())
+	return &api.File{ASTFile: astFile, Position: &pos}
+}
+
+func TestScopeIsGenerated(t *testing.T) {
+	t.Parallel()
+
+	s := &scope{generated: &config.GeneratedConfig{
+		Patterns: config.MultiRegex{{Regexp: regexp.MustCompile(`^// Autogenerated by mytool`)}},
+	}}
+
+	cases := []struct {
+		src       string
+		generated bool
+	}{
+		{"// Code generated by stringer. DO NOT EDIT.\n\npackage foo\n", true},
+		{"// Copyright\n\n// Code generated by protoc-gen-go. DO NOT EDIT.\npackage foo\n", true},
+		{"// Autogenerated by mytool v1\npackage foo\n", true},
+		{"package foo\n\n// Code generated by stringer. DO NOT EDIT.\n", false},
+		{"// code generated, do not edit\npackage foo\n", false},
+		{"/* Code generated by stringer. DO NOT EDIT. */\npackage foo\n", false},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.generated, s.isGenerated(parseTestFile(t, "/src/foo/foo.go", c.src)), c.src)
+	}
+}
@@ -4,6 +4,7 @@ package files
 import (
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // FileExists returns wether a file exists
@@ -40,3 +41,9 @@ func Getwd() string {
 	wd, _ := os.Getwd()
 	return wd
 }
+
+// ContainsPath returns wether path is located inside of dir
+func ContainsPath(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,57 @@
+// Package glob matches slash separated paths against glob patterns
+// supporting ** to match any number of path segments
+package glob
+
+import (
+	"path"
+	"strings"
+)
+
+const (
+	separator  = "/"
+	doubleStar = "**"
+)
+
+// Validate returns an error if the pattern is malformed
+func Validate(pattern string) error {
+	for _, segment := range strings.Split(pattern, separator) {
+		if segment == doubleStar {
+			continue
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Match returns wether the slash separated path matches the pattern
+// malformed patterns do not match any path
+func Match(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, separator), strings.Split(name, separator))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == doubleStar {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
@@ -0,0 +1,37 @@
+package glob
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{"internal/**/zz_generated_*.go", "internal/zz_generated_foo.go", true},
+		{"internal/**/zz_generated_*.go", "internal/a/b/zz_generated_foo.go", true},
+		{"internal/**/zz_generated_*.go", "pkg/internal/zz_generated_foo.go", false},
+		{"internal/**/zz_generated_*.go", "internal/a/foo.go", false},
+		{"**/*_mock.go", "foo_mock.go", true},
+		{"**/*_mock.go", "a/b/foo_mock.go", true},
+		{"*.go", "a/foo.go", false},
+		{"a/**", "a/b/c", true},
+		{"a/?.go", "a/b.go", true},
+		{"a/[", "a/[", false},
+	} {
+		assert.Equal(t, c.match, Match(c.pattern, c.name), "%s %s", c.pattern, c.name)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, Validate("internal/**/*.go"))
+	assert.Error(t, Validate("internal/[/*.go"))
+}
@@ -12,9 +12,18 @@ import (
 	"github.com/liut0/gomultilinter/internal/log"
 )
 
+// installedLinter maps the packages to the *.so files which were already
+// installed by this process, since go plugins can only be opened once per
+// process the packages do not have to be rebuilt for nested configs
+var installedLinter = map[string]string{}
+
 // installLinter downloads a package (if not available locally)
 // and builds (if not yet builded or forceBuild is set) the *.so file to installDir
 func installLinter(linterConf *config.LinterConfig, installDir string, forceBuild bool) (string, error) {
+	if libPath, ok := installedLinter[linterConf.Package]; ok {
+		return libPath, nil
+	}
+
 	pkgImportPath, foundLocally := resolveImportPath(linterConf.Package)
 
 	if !foundLocally {
@@ -23,7 +32,13 @@ func installLinter(linterConf *config.LinterConfig, installDir string, forceBuil
 		}
 	}
 
-	return buildPlugin(pkgImportPath, installDir, forceBuild)
+	libPath, err := buildPlugin(pkgImportPath, installDir, forceBuild)
+	if err != nil {
+		return "", err
+	}
+
+	installedLinter[linterConf.Package] = libPath
+	return libPath, nil
 }
 
 func buildPlugin(pkg, installDir string, forceBuild bool) (string, error) {
@@ -69,10 +84,9 @@ func resolveImportPath(pkg string) (string, bool) {
 	return importPkg.ImportPath, true
 }
 
-// resolvePluginPath expands the path and checks wether a file
-// at the given location exists
+// resolvePluginPath checks wether a file at the given location exists
+// the path is already expanded by the config
 func resolvePluginPath(pluginPath string) (string, error) {
-	pluginPath = os.ExpandEnv(pluginPath)
 	if files.FileExists(pluginPath) {
 		return pluginPath, nil
 	}
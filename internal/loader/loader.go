@@ -22,6 +22,12 @@ func LoadLinter(conf *config.Config) ([]api.Linter, error) {
 
 	linters := make([]api.Linter, 0, len(conf.Linter))
 	for _, linterConf := range conf.Linter {
+		if !conf.LinterSelection.MaySelect(linterConf) {
+			log.WithFields("name", linterConf.Name, "package", linterConf.Package, "plugin_path", linterConf.PluginPath).
+				Debug("skipping unselected linter")
+			continue
+		}
+
 		var (
 			linterLibPath string
 			err           error
@@ -42,6 +48,11 @@ func LoadLinter(conf *config.Config) ([]api.Linter, error) {
 			return nil, err
 		}
 
+		if !conf.LinterSelection.Selects(linterConf, linter.Name()) {
+			log.WithFields("linter", linter.Name()).Debug("skipping unselected linter")
+			continue
+		}
+
 		linters = append(linters, linter)
 	}
 
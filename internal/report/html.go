@@ -0,0 +1,255 @@
+// Package report generates reports out of the collected issues
+package report
+
+import (
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"html"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/liut0/gomultilinter/api"
+	"github.com/liut0/gomultilinter/internal/checker/issue"
+	"github.com/liut0/gomultilinter/internal/log"
+)
+
+const (
+	noPackage = "(none)"
+)
+
+var (
+	htmlTemplate = template.Must(template.New("report").Parse(htmlReportTemplate))
+)
+
+type htmlReport struct {
+	Generated string
+	Total     int
+	Counts    []*countGroup
+	Issues    []*htmlIssue
+	Files     []*htmlFile
+}
+
+type countGroup struct {
+	Title  string
+	Counts []*count
+}
+
+type count struct {
+	Name  string
+	Count int
+}
+
+type htmlIssue struct {
+	FileID   string
+	Path     string
+	Line     int
+	Col      int
+	Severity string
+	Linter   string
+	Category string
+	Message  string
+	Package  string
+}
+
+type htmlFile struct {
+	ID     string
+	Path   string
+	Issues int
+	Lines  []*htmlLine
+}
+
+type htmlLine struct {
+	No     int
+	Source template.HTML
+	Issues []*htmlIssue
+}
+
+// WriteHTML writes a self-contained html report of the issues
+// containing the counts per severity, linter, category and package,
+// a sortable table of all issues and the highlighted source of all files with issues
+// the sources of the files are read through sources
+func WriteHTML(out io.Writer, issues []*issue.LinterIssue, sources *issue.Sources) error {
+	report := &htmlReport{
+		Generated: time.Now().Format(time.RFC1123),
+		Total:     len(issues),
+	}
+
+	severities := map[string]int{}
+	linters := map[string]int{}
+	categories := map[string]int{}
+	packages := map[string]int{}
+
+	fileIndex := map[string]*htmlFile{}
+	fileIssues := map[string]map[int][]*htmlIssue{}
+
+	for _, iss := range issues {
+		hIss := &htmlIssue{
+			Path:     iss.Path.String(),
+			Line:     iss.Line(),
+			Col:      iss.Col(),
+			Severity: strings.ToLower(iss.Severity.String()),
+			Linter:   iss.Linter,
+			Category: iss.Category,
+			Message:  iss.Message,
+			Package:  iss.Package,
+		}
+		if hIss.Package == "" {
+			hIss.Package = noPackage
+		}
+
+		severities[hIss.Severity]++
+		linters[hIss.Linter]++
+		categories[hIss.Category]++
+		packages[hIss.Package]++
+
+		if iss.Position.Filename != "" {
+			f, ok := fileIndex[iss.Path.Abs]
+			if !ok {
+				f = &htmlFile{ID: fmt.Sprintf("file-%d", len(fileIndex)+1), Path: hIss.Path}
+				fileIndex[iss.Path.Abs] = f
+				fileIssues[iss.Path.Abs] = map[int][]*htmlIssue{}
+			}
+			f.Issues++
+			hIss.FileID = f.ID
+			fileIssues[iss.Path.Abs][hIss.Line] = append(fileIssues[iss.Path.Abs][hIss.Line], hIss)
+		}
+
+		report.Issues = append(report.Issues, hIss)
+	}
+
+	for abs, f := range fileIndex {
+		f.Lines = sourceLines(sources, abs, fileIssues[abs])
+		report.Files = append(report.Files, f)
+	}
+	sort.Slice(report.Files, func(i, j int) bool {
+		return report.Files[i].Path < report.Files[j].Path
+	})
+
+	// severities ordered by their level
+	severityCounts := &countGroup{Title: "Severity"}
+	for _, severity := range []api.Severity{api.SeverityError, api.SeverityWarning, api.SeverityInfo} {
+		name := strings.ToLower(severity.String())
+		if severities[name] > 0 {
+			severityCounts.Counts = append(severityCounts.Counts, &count{Name: name, Count: severities[name]})
+		}
+	}
+	report.Counts = []*countGroup{
+		severityCounts,
+		{Title: "Linter", Counts: sortedCounts(linters)},
+		{Title: "Category", Counts: sortedCounts(categories)},
+		{Title: "Package", Counts: sortedCounts(packages)},
+	}
+
+	return htmlTemplate.Execute(out, report)
+}
+
+// sortedCounts returns the counts sorted descending by their count
+func sortedCounts(counts map[string]int) []*count {
+	sorted := make([]*count, 0, len(counts))
+	for name, c := range counts {
+		sorted = append(sorted, &count{Name: name, Count: c})
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// sourceLines returns the highlighted lines of the file
+// including the issues per line
+// issues without a matching line (e.g. unreadable file) are added to the first line
+func sourceLines(sources *issue.Sources, path string, issues map[int][]*htmlIssue) []*htmlLine {
+	src, err := sources.Content(path)
+	if err != nil {
+		log.WithFields("err", err, "path", path).Debug("could not read source file")
+	}
+
+	highlighted := strings.Split(highlight(src, strings.HasSuffix(path, ".go")), "\n")
+	lines := make([]*htmlLine, 0, len(highlighted))
+	for i, source := range highlighted {
+		lines = append(lines, &htmlLine{
+			No:     i + 1,
+			Source: template.HTML(source),
+			Issues: issues[i+1],
+		})
+	}
+
+	for lineNo, lineIssues := range issues {
+		if lineNo <= 0 || lineNo > len(lines) {
+			lines[0].Issues = append(lines[0].Issues, lineIssues...)
+		}
+	}
+	return lines
+}
+
+// highlight returns the escaped html of the source
+// tokens of go sources are wrapped into spans of their token class
+// spans never contain line breaks, so the result can be split into lines
+func highlight(src []byte, isGo bool) string {
+	if !isGo {
+		return html.EscapeString(string(src))
+	}
+
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+
+	var s scanner.Scanner
+	s.Init(file, src, func(token.Position, string) {}, scanner.ScanComments)
+
+	var out strings.Builder
+	offset := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+
+		class := tokenClass(tok)
+		if class == "" {
+			continue
+		}
+
+		start := file.Offset(pos)
+		end := start + len(lit)
+		if lit == "" || start < offset || end > len(src) {
+			continue
+		}
+
+		out.WriteString(html.EscapeString(string(src[offset:start])))
+		for i, part := range strings.Split(string(src[start:end]), "\n") {
+			if i > 0 {
+				out.WriteString("\n")
+			}
+			if part != "" {
+				fmt.Fprintf(&out, `<span class="%s">%s</span>`, class, html.EscapeString(part))
+			}
+		}
+		offset = end
+	}
+	out.WriteString(html.EscapeString(string(src[offset:])))
+
+	return out.String()
+}
+
+func tokenClass(tok token.Token) string {
+	switch {
+	case tok == token.COMMENT:
+		return "com"
+	case tok == token.STRING || tok == token.CHAR:
+		return "str"
+	case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+		return "num"
+	case tok.IsKeyword():
+		return "kw"
+	default:
+		return ""
+	}
+}
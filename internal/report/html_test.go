@@ -0,0 +1,80 @@
+package report
+
+import (
+	"bytes"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liut0/gomultilinter/api"
+	"github.com/liut0/gomultilinter/internal/checker/issue"
+	"github.com/liut0/gomultilinter/internal/checker/overlay"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteHTML(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "gomultilinter")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "foo.go")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("package foo\n"), os.ModePerm))
+
+	// the source is read from the overlay instead of the file on the disk
+	sources := issue.NewSources(overlay.Overlay{
+		path: []byte("package foo\n\n// Foo <does> nothing\nfunc Foo() {\n\tbar := \"baz\"\n}\n"),
+	})
+
+	newIssue := func(line int, severity api.Severity, linter, category, msg string) *issue.LinterIssue {
+		iss := issue.ToLinterIssue(&api.Issue{
+			Position: token.Position{Filename: path, Line: line, Column: 1},
+			Severity: severity,
+			Category: category,
+			Message:  msg,
+		}, linter)
+		iss.Package = "example.com/foo"
+		return iss
+	}
+
+	var out bytes.Buffer
+	assert.NoError(t, WriteHTML(&out, []*issue.LinterIssue{
+		newIssue(4, api.SeverityWarning, "golint", "comments", "comment on <Foo>"),
+		newIssue(5, api.SeverityError, "vet", "unused", "bar declared but not used"),
+		newIssue(5, api.SeverityWarning, "golint", "naming", "bad name"),
+		issue.ToLinterIssue(&api.Issue{Severity: api.SeverityError, Message: "linter failed"}, "foo"),
+	}, sources))
+	report := out.String()
+
+	// dashboard
+	assert.Contains(t, report, `<tr><td>golint</td><td class="count">2</td></tr>`)
+	assert.Contains(t, report, `<tr><td>error</td><td class="count">2</td></tr>`)
+	assert.Contains(t, report, `<tr><td>example.com/foo</td><td class="count">3</td></tr>`)
+	assert.Contains(t, report, `<tr><td>(none)</td><td class="count">1</td></tr>`)
+
+	// issue table
+	assert.Contains(t, report, `<a href="#file-1-5">`)
+	assert.Contains(t, report, `comment on &lt;Foo&gt;`)
+	assert.Contains(t, report, `linter failed`)
+
+	// file page
+	assert.Contains(t, report, `<section class="page" id="file-1">`)
+	assert.Contains(t, report, `<span class="com">// Foo &lt;does&gt; nothing</span>`)
+	assert.Contains(t, report, `<div class="line marked" id="file-1-5"><span class="no">5</span><span class="code">`+"\t"+`bar := <span class="str">&#34;baz&#34;</span></span></div>`)
+	assert.Contains(t, report, `<div class="marker warning">golint (naming): bad name</div>`)
+	assert.Contains(t, report, `<div class="line" id="file-1-1"><span class="no">1</span><span class="code"><span class="kw">package</span> foo</span></div>`)
+}
+
+func TestHighlight(t *testing.T) {
+	t.Parallel()
+
+	src := []byte("/* a\nb */\nx := `c\nd` + 1")
+	assert.Equal(t, `<span class="com">/* a</span>
+<span class="com">b */</span>
+x := <span class="str">`+"`c"+`</span>
+<span class="str">`+"d`"+`</span> + <span class="num">1</span>`, highlight(src, true))
+	assert.Equal(t, "a &lt; b", highlight([]byte("a < b"), false))
+}
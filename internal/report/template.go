@@ -0,0 +1,139 @@
+package report
+
+// htmlReportTemplate is the template of the html report
+// styles and scripts are inlined to keep the report self-contained,
+// the files are shown as pages via their anchor (:target)
+const htmlReportTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>gomultilinter report</title>
+<style>
+body { font-family: sans-serif; margin: 0; color: #222; }
+header { background: #2c3e50; color: #fff; padding: 12px 20px; }
+header a { color: #fff; }
+main { padding: 0 20px 20px; }
+h2 { border-bottom: 1px solid #ddd; padding-bottom: 4px; }
+.dashboard { display: flex; flex-wrap: wrap; gap: 20px; }
+.card { border: 1px solid #ddd; border-radius: 4px; padding: 8px 12px; min-width: 200px; max-height: 320px; overflow: auto; }
+.card h3 { margin: 4px 0 8px; font-size: 1em; }
+.card td.count { text-align: right; padding-left: 16px; }
+table.issues { border-collapse: collapse; width: 100%; }
+table.issues th { cursor: pointer; background: #eee; text-align: left; user-select: none; }
+table.issues th.asc::after { content: " \25B2"; }
+table.issues th.desc::after { content: " \25BC"; }
+table.issues td, table.issues th { border: 1px solid #ddd; padding: 4px 6px; }
+.error { color: #c0392b; }
+.warning { color: #d68910; }
+.info { color: #2874a6; }
+.page { display: none; }
+.page:target { display: block; }
+pre.source { margin: 0; font-size: 13px; }
+.line { display: flex; }
+.line .no { width: 4em; text-align: right; padding-right: 8px; color: #999; user-select: none; flex-shrink: 0; }
+.line .code { white-space: pre; }
+.line.marked { background: #fdebd0; }
+.marker { margin-left: 4em; padding: 2px 8px; font-family: sans-serif; font-size: 12px; border-left: 3px solid; background: #fafafa; }
+.kw { color: #8e44ad; font-weight: bold; }
+.str { color: #27ae60; }
+.com { color: #7f8c8d; font-style: italic; }
+.num { color: #d35400; }
+</style>
+</head>
+<body>
+<header><a href="#report">gomultilinter report</a> &middot; {{.Total}} issues &middot; {{.Generated}}</header>
+<main>
+<section id="report">
+<h2>Dashboard</h2>
+<div class="dashboard">
+{{- range .Counts}}
+<div class="card">
+<h3>{{.Title}}</h3>
+<table>
+{{- range .Counts}}
+<tr><td>{{.Name}}</td><td class="count">{{.Count}}</td></tr>
+{{- end}}
+</table>
+</div>
+{{- end}}
+</div>
+<h2>Issues</h2>
+<table class="issues" id="issues">
+<thead><tr><th>File</th><th data-type="number">Line</th><th data-type="number">Col</th><th>Severity</th><th>Linter</th><th>Category</th><th>Package</th><th>Message</th></tr></thead>
+<tbody>
+{{- range .Issues}}
+<tr><td>{{if .FileID}}<a href="#{{.FileID}}-{{.Line}}">{{.Path}}</a>{{else}}{{.Path}}{{end}}</td><td>{{.Line}}</td><td>{{.Col}}</td><td class="{{.Severity}}">{{.Severity}}</td><td>{{.Linter}}</td><td>{{.Category}}</td><td>{{.Package}}</td><td>{{.Message}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+<h2>Files</h2>
+<ul>
+{{- range .Files}}
+<li><a href="#{{.ID}}">{{.Path}}</a> ({{.Issues}})</li>
+{{- end}}
+</ul>
+</section>
+{{- range $file := .Files}}
+<section class="page" id="{{$file.ID}}">
+<h2>{{$file.Path}} ({{$file.Issues}})</h2>
+<p><a href="#report">&larr; back to report</a></p>
+<pre class="source">
+{{- range $file.Lines}}
+<div class="line{{if .Issues}} marked{{end}}" id="{{$file.ID}}-{{.No}}"><span class="no">{{.No}}</span><span class="code">{{.Source}}</span></div>
+{{- range .Issues}}
+<div class="marker {{.Severity}}">{{.Linter}}{{if .Category}} ({{.Category}}){{end}}: {{.Message}}</div>
+{{- end}}
+{{- end}}
+</pre>
+</section>
+{{- end}}
+</main>
+<script>
+(function() {
+	// show the page of a line anchor
+	function showPage() {
+		var id = location.hash.substring(1);
+		var el = id && document.getElementById(id);
+		var pages = document.querySelectorAll('.page');
+		for (var i = 0; i < pages.length; i++) {
+			pages[i].style.display = el && pages[i].contains(el) ? 'block' : '';
+		}
+		if (el) {
+			el.scrollIntoView();
+		}
+	}
+	window.addEventListener('hashchange', showPage);
+	showPage();
+
+	// sortable issue table
+	var table = document.getElementById('issues');
+	var headers = table.querySelectorAll('th');
+	for (var i = 0; i < headers.length; i++) {
+		headers[i].addEventListener('click', sortBy(i));
+	}
+	function sortBy(col) {
+		return function() {
+			var th = headers[col];
+			var asc = !th.classList.contains('asc');
+			for (var i = 0; i < headers.length; i++) {
+				headers[i].classList.remove('asc', 'desc');
+			}
+			th.classList.add(asc ? 'asc' : 'desc');
+			var numeric = th.getAttribute('data-type') === 'number';
+			var body = table.tBodies[0];
+			var rows = Array.prototype.slice.call(body.rows);
+			rows.sort(function(a, b) {
+				var x = a.cells[col].textContent, y = b.cells[col].textContent;
+				var cmp = numeric ? x - y : x.localeCompare(y);
+				return asc ? cmp : -cmp;
+			});
+			for (var i = 0; i < rows.length; i++) {
+				body.appendChild(rows[i]);
+			}
+		};
+	}
+})();
+</script>
+</body>
+</html>
+`
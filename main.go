@@ -3,10 +3,16 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"strings"
 
+	"github.com/liut0/gomultilinter/api"
 	"github.com/liut0/gomultilinter/config"
 	"github.com/liut0/gomultilinter/internal/checker"
+	"github.com/liut0/gomultilinter/internal/checker/imports"
+	"github.com/liut0/gomultilinter/internal/checker/issue"
+	"github.com/liut0/gomultilinter/internal/checker/overlay"
 	"github.com/liut0/gomultilinter/internal/loader"
 	"github.com/liut0/gomultilinter/internal/log"
 )
@@ -14,8 +20,18 @@ import (
 const (
 	exitSuccess = 0
 
+	// same as log.Fatal()
+	exitError = 1
+
 	// 2 becaus log.Fatal() uses 1
 	exitIssues = 2
+
+	profileEnv = "GOMULTILINTER_PROFILE"
+
+	cmdConfig         = "config"
+	cmdConfigValidate = "validate"
+
+	msgHiddenIssues = "%d of %d issues hidden by the max issues limits\n"
 )
 
 type flags struct {
@@ -24,20 +40,44 @@ type flags struct {
 	forceUpdate  bool
 	installOnly  bool
 	noExitStatus bool
+	failOn       string
+	printConfig  bool
+	summary      bool
+	htmlFile     string
+	profile      string
+	buildTags    string
+
+	metricsFile string
+	metricsTop  int
+	cpuProfile  string
+	memProfile  string
+
+	stdinFilename string
+	overlayFile   string
+
+	enableLinter  string
+	disableLinter string
+	onlyLinter    string
 }
 
 func usage() {
 	fmt.Fprintf(os.Stderr,
 		`usage: %s [flags] <targets>
+       %s [flags] config validate [config files]
 
 targets:
     none         current directory including all sub-directoreis, same as './...'
     packages     where a '/...' suffix includes all sub-packages
     directories  where a '/...' suffix includes all sub-directories
-    files        all must belong to a single package
+    files        only issues of the files are reported, non go files are skipped
+
+targets of different types can be mixed
+
+config validate validates the specified config files (including the files they extend)
+or the config file resolved by the -config flag/the working directory
 
 flags:
-`, os.Args[0])
+`, os.Args[0], os.Args[0])
 
 	flag.PrintDefaults()
 }
@@ -50,12 +90,97 @@ func main() {
 	flag.BoolVar(&cliFlags.forceUpdate, "u", false, "force update/rebuild of linters")
 	flag.BoolVar(&cliFlags.installOnly, "install-only", false, "build/install/validate plugins only, do not lint")
 	flag.BoolVar(&cliFlags.noExitStatus, "no-exit-status", false, "sets exit status only to non 0 if an underlying error occurs")
+	flag.StringVar(&cliFlags.profile, "profile", "", "name of the config profile to use, defaults to $"+profileEnv)
+	flag.BoolVar(&cliFlags.printConfig, "print-config", false, "print the resolved configuration and exit")
+	flag.BoolVar(&cliFlags.summary, "summary", false, "print a summary of the issues and the analysed packages/files to stderr")
+	flag.StringVar(&cliFlags.htmlFile, "html", "", "write a self-contained html report of all issues to this file")
+	flag.StringVar(&cliFlags.metricsFile, "metrics", "", "write per linter/package performance metrics as json to this file")
+	flag.IntVar(&cliFlags.metricsTop, "metrics-top", 10, "number of the slowest packages per linter in the metrics file")
+	flag.StringVar(&cliFlags.cpuProfile, "cpuprofile", "", "write a cpu profile to this file")
+	flag.StringVar(&cliFlags.memProfile, "memprofile", "", "write a memory profile to this file")
+	flag.StringVar(&cliFlags.enableLinter, "enable", "", "comma separated names of linters to enable which are disabled in the config")
+	flag.StringVar(&cliFlags.disableLinter, "disable", "", "comma separated names of linters to disable")
+	flag.StringVar(&cliFlags.onlyLinter, "only", "", "comma separated names of the only linters to use")
+	flag.StringVar(&cliFlags.buildTags, "tags", "", "comma or space separated build tags which are added to all build contexts")
+	flag.StringVar(&cliFlags.stdinFilename, "stdin-filename", "", "read the content of this file from stdin, the file is linted if no targets are passed")
+	flag.StringVar(&cliFlags.overlayFile, "overlay", "", "json file in the format of go build -overlay which replaces the contents of files")
+	flag.StringVar(&cliFlags.failOn, "fail-on", "", "min severity (info, warning, error) of issues which result in exit status 2")
 	flag.Parse()
 
+	if args := flag.Args(); len(args) >= 2 && args[0] == cmdConfig && args[1] == cmdConfigValidate {
+		os.Exit(validateConfigCMD(cliFlags, args[2:]))
+	}
+
 	os.Exit(mainCMD(cliFlags))
 }
 
+func validateConfigCMD(cliFlags *flags, paths []string) int {
+	config.SetVerbose(cliFlags.verbose)
+
+	var errs []error
+	if len(paths) == 0 {
+		conf, err := config.ReadConfig(cliFlags.configFile, cliFlags.verbose, cliFlags.forceUpdate)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			errs = append(errs, validateNestedConfigs(conf)...)
+		}
+	}
+
+	for _, path := range paths {
+		if err := config.ValidateFile(path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, err := range errs {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	if len(errs) > 0 {
+		return exitError
+	}
+	return exitSuccess
+}
+
+// validateNestedConfigs validates the nested config files
+// in the working directory and its sub directories which are not skipped
+func validateNestedConfigs(conf *config.Config) []error {
+	wd, err := os.Getwd()
+	if err != nil {
+		log.WithFields("err", err).Debug("could not read working directory")
+		return []error{fmt.Errorf("could not read working directory %v", err)}
+	}
+
+	resolver := &imports.Resolver{
+		SkipDirs:  conf.SkipDirs,
+		GitIgnore: conf.GitIgnore,
+	}
+	dirs, err := resolver.SubDirs(wd)
+	if err != nil {
+		log.WithFields("err", err).Debug("could not walk working directory")
+		return []error{fmt.Errorf("could not walk working directory %v", err)}
+	}
+
+	return config.NewTree(conf).Validate(dirs)
+}
+
+// mainCMD lints the targets and returns the exit status
+// errors are returned as exitError instead of calling log.Fatal
+// to run the deferred profile writers
 func mainCMD(cliFlags *flags) int {
+	if cliFlags.cpuProfile != "" {
+		stopCPUProfile, err := startCPUProfile(cliFlags.cpuProfile)
+		if err != nil {
+			log.WithFields("err", err).Error()
+			return exitError
+		}
+		defer stopCPUProfile()
+	}
+	if cliFlags.memProfile != "" {
+		defer writeMemProfile(cliFlags.memProfile)
+	}
+
 	metrics := newMetrics()
 	metricsMain := metrics.newEntry("main")
 	defer metrics.log()
@@ -66,42 +191,195 @@ func mainCMD(cliFlags *flags) int {
 	metricsConf := metrics.newEntry("conf")
 	conf, err := config.ReadConfig(cliFlags.configFile, cliFlags.verbose, cliFlags.forceUpdate)
 	if err != nil {
-		log.WithFields("err", err).Fatal()
+		log.WithFields("err", err).Error()
+		return exitError
+	}
+	if profile := profileName(cliFlags); profile != "" {
+		if err := conf.SelectProfile(profile); err != nil {
+			log.WithFields("err", err).Error()
+			return exitError
+		}
+	}
+	conf.LinterSelection = config.NewLinterSelection(cliFlags.enableLinter, cliFlags.disableLinter, cliFlags.onlyLinter)
+	conf.BuildTags = append(append([]string{}, conf.BuildTags...), parseBuildTags(cliFlags.buildTags)...)
+	if cliFlags.failOn != "" {
+		if err := conf.FailOn.UnmarshalText([]byte(cliFlags.failOn)); err != nil {
+			log.WithFields("err", err).Error("invalid fail-on flag")
+			return exitError
+		}
 	}
 	metricsConf.done()
 
+	if cliFlags.printConfig {
+		out, err := conf.Marshal()
+		if err != nil {
+			log.WithFields("err", err).Error("could not marshal config")
+			return exitError
+		}
+		fmt.Print(string(out))
+		return exitSuccess
+	}
+
 	metricsLoadPlugins := metrics.newEntry("load_plugins")
 	linter, err := loader.LoadLinter(conf)
 	if err != nil {
-		log.WithFields("err", err).Fatal()
+		log.WithFields("err", err).Error()
+		return exitError
 	}
 	metricsLoadPlugins.done()
 	if cliFlags.installOnly {
+		// only the linters of the root config are loaded, nested ones are not installed
+		if unknown := conf.LinterSelection.Unknown(); len(unknown) > 0 {
+			log.WithFields("linters", unknown).Error("unknown linters in -enable, -disable or -only")
+			return exitError
+		}
 		return exitSuccess
 	}
 
 	metricsLoadChecker := metrics.newEntry("load_checker")
-	ckr, err := checker.NewChecker(conf, linter)
+	ckr, err := checker.NewChecker(conf, linter, loader.LoadLinter)
 	if err != nil {
-		log.WithFields("err", err).Fatal()
+		log.WithFields("err", err).Error()
+		return exitError
 	}
 
-	if err := ckr.Load(flag.Args()...); err != nil {
-		log.WithFields("err", err).Fatal()
+	targets := flag.Args()
+	if cliFlags.overlayFile != "" || cliFlags.stdinFilename != "" {
+		o, err := readOverlay(cliFlags)
+		if err != nil {
+			log.WithFields("err", err).Error()
+			return exitError
+		}
+		ckr.SetOverlay(o)
+
+		if len(targets) == 0 && cliFlags.stdinFilename != "" {
+			targets = []string{cliFlags.stdinFilename}
+		}
+	}
+
+	if err := ckr.Load(targets...); err != nil {
+		log.WithFields("err", err).Error()
+		return exitError
+	}
+	if unknown := conf.LinterSelection.Unknown(); len(unknown) > 0 {
+		log.WithFields("linters", unknown).Error("unknown linters in -enable, -disable or -only")
+		return exitError
 	}
 	metricsLoadChecker.done()
 
+	if cliFlags.metricsFile != "" {
+		ckr.EnableMetrics()
+	}
+
+	// failed is set if an output could not be written,
+	// the other outputs are still written before returning exitError
+	failed := false
+
 	metricsLinters := metrics.newEntry("linters")
-	issues := ckr.Run()
+	issues, err := ckr.Run()
 	metricsLinters.done()
+	if err != nil {
+		log.WithFields("err", err).Error()
+		failed = true
+	}
+
+	if cliFlags.metricsFile != "" {
+		metricsMain.done()
+		if err := metrics.writeFile(cliFlags.metricsFile, ckr.Metrics(cliFlags.metricsTop)); err != nil {
+			log.WithFields("err", err).Error()
+			failed = true
+		}
+	}
+
+	stats := ckr.Stats()
+	if stats.Hidden > 0 {
+		// written to stderr to keep structured outputs valid
+		fmt.Fprintf(os.Stderr, msgHiddenIssues, stats.Hidden, len(issues))
+	}
+
+	if cliFlags.summary {
+		printSummary(os.Stderr, issues, stats)
+	}
+
+	if cliFlags.htmlFile != "" {
+		if err := writeHTMLReport(cliFlags.htmlFile, issues, ckr.Sources()); err != nil {
+			log.WithFields("err", err).Error()
+			failed = true
+		}
+	}
+
+	if failed {
+		return exitError
+	}
 
 	issuesCount := len(issues)
+	severityCounts := countSeverities(issues)
 
 	log.WithFields("issues_count", issuesCount).Debug("done")
+	if issuesCount > 0 {
+		log.WithFields(
+			"info", severityCounts[api.SeverityInfo],
+			"warning", severityCounts[api.SeverityWarning],
+			"error", severityCounts[api.SeverityError]).Info("issues")
+	}
 
-	if cliFlags.noExitStatus || issuesCount == 0 {
+	if cliFlags.noExitStatus || !failsOn(severityCounts, conf.FailOn.Severity) {
 		return exitSuccess
 	}
 
 	return exitIssues
 }
+
+func profileName(cliFlags *flags) string {
+	if cliFlags.profile != "" {
+		return cliFlags.profile
+	}
+	return os.Getenv(profileEnv)
+}
+
+// readOverlay reads the overlay file and the content of
+// the stdin file from stdin
+func readOverlay(cliFlags *flags) (overlay.Overlay, error) {
+	o := overlay.Overlay{}
+	if cliFlags.overlayFile != "" {
+		var err error
+		if o, err = overlay.ReadFile(cliFlags.overlayFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if cliFlags.stdinFilename != "" {
+		content, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			log.WithFields("err", err).Debug("could not read stdin")
+			return nil, fmt.Errorf("could not read stdin %v", err)
+		}
+		o.Add(cliFlags.stdinFilename, content)
+	}
+	return o, nil
+}
+
+// parseBuildTags splits a comma or space separated list of build tags
+func parseBuildTags(tags string) []string {
+	return strings.FieldsFunc(tags, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
+func countSeverities(issues []*issue.LinterIssue) map[api.Severity]int {
+	counts := map[api.Severity]int{}
+	for _, iss := range issues {
+		counts[iss.Severity]++
+	}
+	return counts
+}
+
+// failsOn returns wether any issue has at least the severity failOn
+func failsOn(severityCounts map[api.Severity]int, failOn api.Severity) bool {
+	for severity, count := range severityCounts {
+		if severity >= failOn && count > 0 {
+			return true
+		}
+	}
+	return false
+}
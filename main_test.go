@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"go/token"
 	"os"
 	"reflect"
@@ -37,13 +38,14 @@ func TestMainAll(t *testing.T) {
 	linter, err := loader.LoadLinter(conf)
 	assert.NoError(t, err)
 
-	ckr, err := checker.NewChecker(conf, linter)
+	ckr, err := checker.NewChecker(conf, linter, loader.LoadLinter)
 	assert.NoError(t, err)
 
 	err = ckr.Load("github.com/liut0/gomultilinter/test/data")
 	assert.NoError(t, err)
 
-	issues := ckr.Run()
+	issues, err := ckr.Run()
+	assert.NoError(t, err)
 
 	expectIssues(t, []*issue.LinterIssue{
 		issue.ToLinterIssue(&api.Issue{
@@ -119,3 +121,54 @@ func expectIssues(t *testing.T, expected []*issue.LinterIssue, actual []*issue.L
 		}
 	}
 }
+
+func TestFailsOn(t *testing.T) {
+	counts := countSeverities([]*issue.LinterIssue{
+		issue.ToLinterIssue(&api.Issue{Severity: api.SeverityInfo}, "a"),
+		issue.ToLinterIssue(&api.Issue{Severity: api.SeverityWarning}, "b"),
+		issue.ToLinterIssue(&api.Issue{Severity: api.SeverityWarning}, "b"),
+	})
+
+	assert.Equal(t, map[api.Severity]int{api.SeverityInfo: 1, api.SeverityWarning: 2}, counts)
+	assert.True(t, failsOn(counts, api.SeverityInfo))
+	assert.True(t, failsOn(counts, api.SeverityWarning))
+	assert.False(t, failsOn(counts, api.SeverityError))
+}
+
+func TestPrintSummary(t *testing.T) {
+	var out bytes.Buffer
+	printSummary(&out, []*issue.LinterIssue{
+		issue.ToLinterIssue(&api.Issue{Severity: api.SeverityWarning, Category: "comments"}, "golint"),
+		issue.ToLinterIssue(&api.Issue{Severity: api.SeverityError, Category: "unchecked"}, "errcheck"),
+		issue.ToLinterIssue(&api.Issue{Severity: api.SeverityWarning, Category: "comments"}, "golint"),
+	}, &checker.Stats{
+		Packages:     2,
+		Files:        5,
+		Suppressed:   map[string]int{"nolint": 1, "severity": 3},
+		LinterErrors: map[string]int{"vet": 1},
+	})
+
+	assert.Equal(t, `packages  2
+files     5
+issues    3
+
+severity  issues
+Error     1
+Warning   2
+
+linter    issues
+errcheck  1
+golint    2
+
+category   issues
+comments   2
+unchecked  1
+
+suppressed by  issues
+nolint         1
+severity       3
+
+linter  errors
+vet     1
+`, out.String())
+}
@@ -1,8 +1,13 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"time"
 
+	"github.com/liut0/gomultilinter/internal/checker"
+	"github.com/liut0/gomultilinter/internal/log"
 	"github.com/sirupsen/logrus"
 )
 
@@ -39,3 +44,32 @@ func (m metrics) log() {
 func (m *metricsEntry) done() {
 	m.elapsed = time.Since(m.start)
 }
+
+// metricsFile is the content of the file written by the -metrics flag
+type metricsFile struct {
+	Phases map[string]time.Duration `json:"phases_ns"`
+	*checker.Metrics
+}
+
+// writeFile writes the phases and the metrics of the linters as json to the file
+func (m metrics) writeFile(path string, linterMetrics *checker.Metrics) error {
+	phases := make(map[string]time.Duration, len(m))
+	for k, v := range m {
+		phases[k] = v.elapsed
+	}
+
+	for _, l := range linterMetrics.Linters {
+		log.WithFields("linter", l.Linter, "duration", l.Duration).Debug("linter metrics")
+	}
+
+	content, err := json.MarshalIndent(&metricsFile{Phases: phases, Metrics: linterMetrics}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(path, content, 0644); err != nil {
+		log.WithFields("err", err, "path", path).Debug("could not write metrics file")
+		return fmt.Errorf("could not write metrics file %v", err)
+	}
+	return nil
+}
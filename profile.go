@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+
+	"github.com/liut0/gomultilinter/internal/log"
+)
+
+// startCPUProfile starts the cpu profiling to the file
+// the returned func stops the profiling
+func startCPUProfile(path string) (func(), error) {
+	f, err := os.Create(path)
+	if err != nil {
+		log.WithFields("err", err, "path", path).Debug("could not create cpu profile")
+		return nil, fmt.Errorf("could not create cpu profile %v", err)
+	}
+
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		log.WithFields("err", err).Debug("could not start cpu profile")
+		return nil, fmt.Errorf("could not start cpu profile %v", err)
+	}
+
+	return func() {
+		pprof.StopCPUProfile()
+		if err := f.Close(); err != nil {
+			log.WithFields("err", err, "path", path).Error("could not write cpu profile")
+		}
+	}, nil
+}
+
+// writeMemProfile writes the heap profile to the file
+func writeMemProfile(path string) {
+	f, err := os.Create(path)
+	if err != nil {
+		log.WithFields("err", err, "path", path).Error("could not create mem profile")
+		return
+	}
+	defer f.Close()
+
+	// get up-to-date statistics
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		log.WithFields("err", err, "path", path).Error("could not write mem profile")
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/liut0/gomultilinter/api"
+	"github.com/liut0/gomultilinter/internal/checker"
+	"github.com/liut0/gomultilinter/internal/checker/issue"
+)
+
+// printSummary prints a summary table of the issues and stats of the run
+func printSummary(out io.Writer, issues []*issue.LinterIssue, stats *checker.Stats) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(w, "packages\t%d\n", stats.Packages)
+	fmt.Fprintf(w, "files\t%d\n", stats.Files)
+	fmt.Fprintf(w, "issues\t%d\n", len(issues))
+	if stats.Hidden > 0 {
+		fmt.Fprintf(w, "hidden by limits\t%d\n", stats.Hidden)
+	}
+
+	severities := map[api.Severity]int{}
+	linters := map[string]int{}
+	categories := map[string]int{}
+	for _, iss := range issues {
+		severities[iss.Severity]++
+		linters[iss.Linter]++
+		categories[iss.Category]++
+	}
+
+	// severities ordered by their level instead of their names
+	if len(issues) > 0 {
+		fmt.Fprint(w, "\nseverity\tissues\n")
+		for _, severity := range []api.Severity{api.SeverityError, api.SeverityWarning, api.SeverityInfo} {
+			if count := severities[severity]; count > 0 {
+				fmt.Fprintf(w, "%s\t%d\n", severity, count)
+			}
+		}
+	}
+
+	printCounts(w, "linter\tissues", linters)
+	printCounts(w, "category\tissues", categories)
+	printCounts(w, "suppressed by\tissues", stats.Suppressed)
+	printCounts(w, "linter\terrors", stats.LinterErrors)
+	printCounts(w, "linter\tpanics", stats.LinterPanics)
+
+	w.Flush()
+}
+
+// printCounts prints the header followed by the counts sorted by their keys
+// nothing is printed if there are no counts
+func printCounts(w io.Writer, header string, counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(w, "\n%s\n", header)
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s\t%d\n", key, counts[key])
+	}
+}